@@ -18,6 +18,10 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	// there's no chance to save when Android kills the app, so the journal will have kept every move
+	if !sol.NoGameLoad {
+		sol.TheBaize.SetSavedGames(sol.LoadSavedGames())
+	}
 	mobile.SetGame(game)
 }
 
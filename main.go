@@ -29,12 +29,21 @@ func main() {
 
 	// pearl from the mudbank: don't have any flags that will overwrite ThePreferences
 	flag.BoolVar(&sol.DebugMode, "debug", false, "turn debug graphics on")
-	flag.BoolVar(&sol.NoGameLoad, "noload", false, "do not load saved game when starting")
+	flag.BoolVar(&sol.NoGameLoad, "noload", false, "do not load (or save over) saved games")
 	flag.BoolVar(&sol.NoGameSave, "nosave", false, "do not save game before exit")
 	flag.BoolVar(&sol.NoCardLerp, "nolerp", false, "do not animate card movements")
 	flag.BoolVar(&sol.NoCardFlip, "noflip", false, "do not animate card flips")
 	flag.BoolVar(&sol.NoShuffle, "noshuf", false, "do not shuffle cards")
 	flag.BoolVar(&sol.NoScrunch, "noscrunch", false, "do not scrunch cards")
+	flag.BoolVar(&sol.DemoMode, "demo", false, "let the computer play some games by itself")
+	flag.StringVar(&sol.DataDir, "datadir", "", "directory to keep preferences, statistics and saved games in")
+	flag.StringVar(&sol.Profile, "profile", "", "play as this profile, making it if need be")
+	flag.StringVar(&sol.ImportFile, "import", "", "add and play a shared custom variant file")
+	var historyFile string
+	flag.StringVar(&historyFile, "history", "", "export the history of finished games to a .csv or .json file, and exit")
+	flag.StringVar(&sol.ImportStatsFile, "importstats", "", "merge statistics exported from another device")
+	var statsFile string
+	flag.StringVar(&statsFile, "exportstats", "", "export the statistics to a .json file, to be imported on another device, and exit")
 	flag.BoolVar(&ui.GenerateIcons, "generateicons", false, "generate icon files")
 
 	flag.Parse()
@@ -45,6 +54,20 @@ func main() {
 		}
 	}
 
+	if statsFile != "" {
+		if err := sol.ExportStatistics(statsFile); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if historyFile != "" {
+		if err := sol.ExportHistory(historyFile); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	ebiten.SetWindowResizable(true) //ebiten panics if a window to maximize is not resizable
 	if ebiten.IsWindowMaximized() || ebiten.IsWindowMinimized() {
 		// GNOME (maybe) annoyingly keeps maximizing the window
@@ -68,9 +91,7 @@ func main() {
 	}
 
 	if !sol.NoGameLoad {
-		if undoStack := sol.LoadUndoStack(); undoStack != nil {
-			sol.TheBaize.SetUndoStack(undoStack)
-		}
+		sol.TheBaize.SetSavedGames(sol.LoadSavedGames())
 	}
 
 	if err := ebiten.RunGame(game); err != nil {
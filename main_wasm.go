@@ -7,6 +7,8 @@ package main
 
 import (
 	"log"
+	"strings"
+	"syscall/js"
 
 	// load png decoder in main package
 	_ "image/png"
@@ -17,11 +19,20 @@ import (
 
 func main() {
 
+	// the landing page can show the game with ?demo on the end of the url, to get an attract screen
+	if strings.Contains(js.Global().Get("location").Get("search").String(), "demo") {
+		sol.DemoMode = true
+	}
+
 	game, err := sol.NewGame()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if !sol.NoGameLoad {
+		sol.TheBaize.SetSavedGames(sol.LoadSavedGames())
+	}
+
 	defer func() {
 		println("main defer cleanup")
 		if !sol.NoGameSave {
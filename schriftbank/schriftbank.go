@@ -27,6 +27,8 @@ var robotoMediumFontBytes []byte
 var (
 	// RobotoRegular14 used by UI toast
 	RobotoRegular14 font.Face
+	// RobotoMedium14 used by UI table headings
+	RobotoMedium14 font.Face
 	// RobotoMedium24 used by UI
 	RobotoMedium24 font.Face
 	// CardSymbolSmall is used to draw the suit symbol under the card ordinal
@@ -63,6 +65,12 @@ func init() {
 		log.Fatal(err)
 	}
 
+	RobotoMedium14 = truetype.NewFace(tt, &truetype.Options{
+		Size:    14,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+
 	RobotoMedium24 = truetype.NewFace(tt, &truetype.Options{
 		Size:    24,
 		DPI:     72,
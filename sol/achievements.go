@@ -0,0 +1,180 @@
+package sol
+
+import (
+	"fmt"
+	"time"
+
+	"oddstream.games/gosol/ui"
+)
+
+// Achievements are defined by the table below; each one is a set of conditions,
+// all of which have to be met, and is checked whenever a game is won (or statistics are imported).
+// Once unlocked, an achievement is kept with the statistics, with when it was unlocked
+
+// Achievement describes something worth doing; conditions that are left empty don't matter
+type Achievement struct {
+	ID          string // kept in the statistics, so never change it
+	Title       string
+	Description string
+
+	// conditions on the game that has just been won
+	Variant    string // won a game of this variant
+	MaxSeconds int    // in this many seconds or less
+	MaxMoves   int    // in this many moves or less
+	NoUndo     bool   // without undoing anything
+	NoCells    bool   // without ever putting a card in a cell
+
+	// conditions on the statistics
+	Wins   int    // won this many games, of all variants
+	Streak int    // a winning streak this long, in one variant
+	Group  string // won every variant in this group of VariantGroups; "*" for every variant that comes with the game
+}
+
+var achievements = []*Achievement{
+	{ID: "first-win", Title: "Off the mark", Description: "Win a game", Wins: 1},
+	{ID: "hundred-wins", Title: "Centurion", Description: "Win 100 games", Wins: 100},
+	{ID: "streak-10", Title: "On a roll", Description: "Win 10 games of a variant in a row", Streak: 10},
+	{ID: "no-undo", Title: "No going back", Description: "Win a game without undoing a move", NoUndo: true},
+	{ID: "freecell-no-cells", Title: "Who needs cells?", Description: "Win Freecell without using a cell", Variant: "Freecell", NoCells: true},
+	{ID: "klondike-draw-three-5m", Title: "Against the clock", Description: "Win Klondike Draw Three in under 5 minutes", Variant: "Klondike Draw Three", MaxSeconds: 299},
+	{ID: "spider-four-suits", Title: "Arachnophile", Description: "Win Spider Four Suits", Variant: "Spider Four Suits"},
+	{ID: "every-spider", Title: "Web master", Description: "Win every kind of Spider", Group: "> Spider"},
+	{ID: "every-variant", Title: "Completist", Description: "Win every variant", Group: "*"},
+}
+
+// gameFacts are what is known about a game that has just been won
+type gameFacts struct {
+	variant   string
+	seconds   int
+	moves     int
+	undos     int
+	usedCells bool
+}
+
+// achievementFacts looks at the game that has just been won
+func (b *Baize) achievementFacts() *gameFacts {
+	gf := &gameFacts{
+		variant: b.LongVariantName(),
+		seconds: int(b.elapsed / time.Second),
+		moves:   b.moves,
+		undos:   b.undos,
+	}
+	for _, sb := range b.undoStack {
+		for _, sp := range sb.Piles {
+			if sp.Category == "Cell" && len(sp.Cards) > 0 {
+				gf.usedCells = true
+			}
+		}
+	}
+	return gf
+}
+
+// wonAll is true if every variant in the group has been won, relaxed or not
+func (s *Statistics) wonAll(group string) bool {
+	var variants []string
+	if group == "*" {
+		for _, v := range VariantGroups["> All"] {
+			if !isCustomVariant(v) {
+				variants = append(variants, v)
+			}
+		}
+	} else {
+		variants = VariantGroups[group]
+	}
+	won := func(v string) bool {
+		stats, ok := s.StatsMap[v]
+		return ok && stats.Won > 0
+	}
+	for _, v := range variants {
+		if !won(v) && !won(v+" Relaxed") {
+			return false
+		}
+	}
+	return len(variants) > 0
+}
+
+// met is true if the achievement's conditions are met; gf is nil if there isn't a game to look at,
+// in which case only achievements that depend on the statistics alone can be met
+func (a *Achievement) met(gf *gameFacts, s *Statistics) bool {
+	if a.Variant != "" || a.MaxSeconds > 0 || a.MaxMoves > 0 || a.NoUndo || a.NoCells {
+		if gf == nil {
+			return false
+		}
+		if a.Variant != "" && a.Variant != gf.variant {
+			return false
+		}
+		if (a.MaxSeconds > 0 && gf.seconds > a.MaxSeconds) || (a.MaxMoves > 0 && gf.moves > a.MaxMoves) {
+			return false
+		}
+		if (a.NoUndo && gf.undos > 0) || (a.NoCells && gf.usedCells) {
+			return false
+		}
+	}
+	if a.Wins > 0 || a.Streak > 0 {
+		var wins, streak int
+		for _, stats := range s.StatsMap {
+			wins += stats.Won
+			if stats.BestStreak > streak {
+				streak = stats.BestStreak
+			}
+		}
+		if wins < a.Wins || streak < a.Streak {
+			return false
+		}
+	}
+	if a.Group != "" && !s.wonAll(a.Group) {
+		return false
+	}
+	return true
+}
+
+// unlockAchievements checks every achievement that hasn't been unlocked yet, and announces the ones that now are
+func (s *Statistics) unlockAchievements(gf *gameFacts) {
+	for _, a := range achievements {
+		if _, ok := s.Achievements[a.ID]; ok || !a.met(gf, s) {
+			continue
+		}
+		if s.Achievements == nil {
+			s.Achievements = make(map[string]time.Time)
+		}
+		s.Achievements[a.ID] = time.Now()
+		TheUI.Toast(fmt.Sprintf("Achievement unlocked: %s", a.Title))
+	}
+}
+
+// mergeAchievements adds achievements unlocked on another device, keeping the earliest time each was unlocked
+func (s *Statistics) mergeAchievements(other map[string]time.Time) {
+	for id, when := range other {
+		if mine, ok := s.Achievements[id]; !ok || when.Before(mine) {
+			if s.Achievements == nil {
+				s.Achievements = make(map[string]time.Time)
+			}
+			s.Achievements[id] = when
+		}
+	}
+}
+
+// ShowAchievementsDrawer lists the achievements, unlocked ones first
+func ShowAchievementsDrawer() {
+	var unlocked, locked []ui.ListEntry
+	for _, a := range achievements {
+		if when, ok := TheStatistics.Achievements[a.ID]; ok {
+			unlocked = append(unlocked, ui.ListEntry{Icon: "check_box", Text: a.Title, Detail: fmt.Sprintf("%s, %s", a.Description, when.Format("2 Jan 2006"))})
+		} else {
+			locked = append(locked, ui.ListEntry{Icon: "check_box_outline_blank", Text: a.Title, Detail: a.Description})
+		}
+	}
+	entries := []ui.ListEntry{{Text: "Achievements", Detail: fmt.Sprintf("%d of %d unlocked", len(unlocked), len(achievements))}}
+	entries = append(entries, unlocked...)
+	TheUI.ShowListDrawer("Achievements", append(entries, locked...))
+}
+
+// isCustomVariant is true if the user made the variant
+func isCustomVariant(v string) bool {
+	for _, cv := range VariantGroups[customGroup] {
+		if cv == v {
+			return true
+		}
+	}
+	return false
+}
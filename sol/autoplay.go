@@ -0,0 +1,77 @@
+package sol
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	autoMoveTicks    = 30  // about half a second between moves
+	demoRestartTicks = 240 // time to admire the finished game before the next demo deal
+)
+
+// DemoVariants are dealt in turn, over and over, when DemoMode is on
+var DemoVariants = []string{"Clock", "Accordion"}
+
+// AutoPlaying returns true if the script is making the moves, rather than the user
+func (b *Baize) AutoPlaying() bool {
+	if _, ok := b.script.(AutoPlayer); !ok {
+		return false
+	}
+	return DemoMode || b.script.Info().autoPlay
+}
+
+// cardsMoving returns true if any card is still on it's way somewhere, or turning over
+func (b *Baize) cardsMoving() bool {
+	for _, p := range b.piles {
+		for _, c := range p.Cards() {
+			if c.Transitioning() || c.Flipping() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// autoPlay is called every tick by Update. It waits for the cards from the last move
+// to settle, then asks the script for another move, which is treated just like a user move
+// (so it gets an undo snapshot, and a won game is recorded)
+func (b *Baize) autoPlay() {
+	if !b.AutoPlaying() || len(b.tail) > 0 || b.cardsMoving() || b.previewing {
+		return
+	}
+	if b.autoTicks > 0 {
+		b.autoTicks--
+		return
+	}
+	if b.autoDone {
+		if DemoMode {
+			b.ChangeVariant(nextDemoVariant())
+		}
+		return
+	}
+	b.autoTicks = autoMoveTicks
+	if b.script.(AutoPlayer).AutoMove() {
+		b.AfterUserMove()
+		if b.Complete() {
+			b.autoDone = true
+			b.autoTicks = demoRestartTicks
+		}
+	} else {
+		b.autoDone = true
+		b.autoTicks = demoRestartTicks
+		TheUI.Toast("No more moves")
+		if !DemoMode {
+			TheUI.ShowFAB("star", ebiten.KeyN)
+		}
+	}
+}
+
+// nextDemoVariant returns the variant to show after the current one
+func nextDemoVariant() string {
+	for i, v := range DemoVariants {
+		if v == ThePreferences.Variant {
+			return DemoVariants[(i+1)%len(DemoVariants)]
+		}
+	}
+	return DemoVariants[0]
+}
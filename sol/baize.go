@@ -6,6 +6,7 @@ import (
 	"image"
 	"log"
 	"runtime"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -35,9 +36,24 @@ type Baize struct {
 	script       ScriptInterface
 	piles        []Pile
 	tail         []*Card // array of cards currently being dragged
-	bookmark     int     // index into undo stack
+	bookmarks    []Bookmark // bookmarks in the current line, see bookmarks.go
+	branches     []*Branch  // lines of play that were left behind
+	previewing   bool       // the cards show an earlier position from the timeline, see timeline.go
+	moves        int           // number of moves made in this game, see timer.go
+	elapsed      time.Duration // time spent playing this game
+	undos        int           // number of times a move has been undone in this game
+	started      time.Time     // when the first move was made, see history.go
+	tally        *tally        // of the undo stack, for the score, see scoring.go
+	scoring      string        // name of the ScoringModel this deal is scored with
 	recycles     int     // number of available stock recycles
+	redeals      int     // number of available redeals
+	autoTicks    int     // countdown to the next move, for games that play themselves
+	autoDone     bool    // a self playing game has run out of moves
 	undoStack    []*SavableBaize
+	savedGames   map[string]*SavedGame // games in progress in other variants, see savedgames.go
+	unsaved      bool                  // the game has changed since it was last saved
+	journalling  bool                  // changes to the undo stack are being written to the journal
+	journalSeq   int64                 // sequence number of the last journal entry
 	dirtyFlags   uint32 // what needs doing when we Update
 	stroke       *input.Stroke
 	dragStart    image.Point
@@ -87,6 +103,8 @@ func (b *Baize) CRC() uint32 {
 	for _, p := range b.piles {
 		lens = append(lens, byte(p.Len()))
 	}
+	// a redeal may leave every pile the same length as before
+	lens = append(lens, byte(b.recycles), byte(b.redeals))
 	return crc32.ChecksumIEEE(lens)
 }
 
@@ -111,10 +129,15 @@ func (b *Baize) NewDeal() {
 
 	b.StopSpinning()
 
+	_, daily := dailyDate(b.script.Stock().Seed())
 	// a virgin game has one state on the undo stack
 	if len(b.undoStack) > 1 && !b.Complete() {
 		TheStatistics.RecordLostGame(b.LongVariantName())
 	}
+	// after the daily challenge, go back to the game it interrupted, unless the challenge is being dealt again
+	if daily && nextSeed == 0 && b.unparkGame() {
+		return
+	}
 
 	b.Reset()
 	for _, p := range b.piles {
@@ -125,6 +148,7 @@ func (b *Baize) NewDeal() {
 	stockPile.FillFromLibrary()
 	stockPile.Shuffle()
 
+	b.scoring = ThePreferences.Scoring
 	b.script.StartGame()
 	b.UndoPush()
 	sound.Play("Fan")
@@ -184,7 +208,16 @@ func (b *Baize) MirrorSlots() {
 func (b *Baize) Reset() {
 	b.tail = nil
 	b.undoStack = nil
-	b.bookmark = 0
+	b.bookmarks = nil
+	b.branches = nil
+	b.moves = 0
+	b.elapsed = 0
+	b.undos = 0
+	b.started = time.Time{}
+	b.tally = nil
+	b.redeals = 0
+	b.autoTicks = 0
+	b.autoDone = false
 
 	if DebugMode {
 		for i := 0; i < len(CardLibrary); i++ {
@@ -235,23 +268,25 @@ func (b *Baize) StartFreshGame() {
 
 	b.dirtyFlags = 0xFFFF
 
+	b.scoring = ThePreferences.Scoring
 	b.script.StartGame()
 	b.UndoPush()
 
 	TheStatistics.WelcomeToast(b.LongVariantName())
 }
 
+// ChangeVariant keeps the current game to come back to later (so it isn't recorded as lost),
+// and resumes the new variant's game if there is one
 func (b *Baize) ChangeVariant(newVariant string) {
-	// a virgin game has one state on the undo stack
-	if len(b.undoStack) > 1 && !b.Complete() {
-		TheStatistics.RecordLostGame(b.LongVariantName())
-	}
+	b.stashGame()
 	ThePreferences.Variant = newVariant
 	b.StartFreshGame()
+	b.resumeGame()
 }
 
 func (b *Baize) SetUndoStack(undoStack []*SavableBaize) {
 	b.undoStack = undoStack
+	b.journal(0, undoStack)
 	sav := b.UndoPeek()
 	b.UpdateFromSavable(sav)
 	b.UpdateStatusbar()
@@ -261,7 +296,7 @@ func (b *Baize) SetUndoStack(undoStack []*SavableBaize) {
 		b.StartSpinning()
 	} else if b.Conformant() {
 		TheUI.ShowFAB("done_all", ebiten.KeyC)
-	} else if b.Stuck() {
+	} else if !b.AutoPlaying() && b.Stuck() {
 		TheUI.Toast("No movable cards")
 		TheUI.ShowFAB("star", ebiten.KeyN)
 	} else {
@@ -361,6 +396,7 @@ func (b *Baize) MakeTail(c *Card) bool {
 
 func (b *Baize) AfterUserMove() {
 	b.script.AfterMove()
+	b.countMove()
 	b.UndoPush()
 	if b.Complete() {
 		TheStatistics.RecordWonGame(b.LongVariantName())
@@ -368,7 +404,7 @@ func (b *Baize) AfterUserMove() {
 		b.StartSpinning()
 	} else if b.Conformant() {
 		TheUI.ShowFAB("done_all", ebiten.KeyC)
-	} else if b.Stuck() {
+	} else if !b.AutoPlaying() && b.Stuck() {
 		TheUI.Toast("No movable cards")
 		TheUI.ShowFAB("star", ebiten.KeyN)
 	} else {
@@ -705,13 +741,24 @@ func (b *Baize) ScaleCards() bool {
 func (b *Baize) PercentComplete() int {
 	var pairs, unsorted, percent int
 	for _, p := range b.piles {
-		if p.Len() > 1 {
+		if _, ok := (p).(*GridCell); ok {
+			// the card in a GridCell pairs with it's neighbour
+			pairs += p.Len()
+		} else if p.Len() > 1 {
 			pairs += p.Len() - 1
 		}
 		unsorted += p.UnsortedPairs()
 	}
+	if pairs == 0 {
+		return 0
+	}
 	// TheUI.SetMiddle(fmt.Sprintf("%d/%d", pairs-unsorted, pairs))
 	percent = (int)(100.0 - util.MapValue(float64(unsorted), 0, float64(pairs), 0.0, 100.0))
+	if percent == 100 && !b.Complete() {
+		// everything is sorted, but not where it has to be (eg left on the tableaux in Freecell),
+		// and only a completed game is 100%
+		percent = 99
+	}
 	return percent
 }
 
@@ -728,6 +775,19 @@ func (b *Baize) SetRecycles(recycles int) {
 	}
 }
 
+func (b *Baize) Redeals() int {
+	return b.redeals
+}
+
+func (b *Baize) SetRedeals(redeals int) {
+	b.redeals = redeals
+	if TheBaize.redeals == 0 {
+		b.script.Stock().SetRune(NORECYCLE_RUNE)
+	} else {
+		b.script.Stock().SetRune(RECYCLE_RUNE)
+	}
+}
+
 func (b *Baize) UpdateStatusbar() {
 	if b.script.Stock().Hidden() {
 		TheUI.SetStock(-1)
@@ -743,9 +803,16 @@ func (b *Baize) UpdateStatusbar() {
 	// 	TheUI.SetMiddle(fmt.Sprintf("len(undoStack) = %d", len(b.undoStack)))
 	// }
 	TheUI.SetPercent(b.PercentComplete())
+	b.tally = tallyUndoStack(b.undoStack)
+	b.updateMiddleStatus()
+	b.updateTimeline()
 }
 
 func (b *Baize) Conformant() bool {
+	if len(b.script.Foundations()) == 0 {
+		// nowhere to collect the cards to (eg Accordion, Clock)
+		return false
+	}
 	for _, p := range b.piles {
 		if !p.Conformant() {
 			return false
@@ -843,9 +910,14 @@ func (b *Baize) Update() error {
 		p.Update()
 	}
 
-	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
-		if inpututil.IsKeyJustReleased(k) {
-			Execute(k)
+	b.autoPlay()
+
+	// keys typed into a text input aren't commands
+	if !TheUI.Typing() {
+		for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+			if inpututil.IsKeyJustReleased(k) {
+				Execute(k)
+			}
 		}
 	}
 
@@ -883,7 +955,7 @@ func (b *Baize) Draw(screen *ebiten.Image) {
 	// var ms runtime.MemStats
 	// runtime.ReadMemStats(&ms)
 	// ebitenutil.DebugPrint(screen, fmt.Sprintf("FPS %v, Alloc %v, NumGC %v", ebiten.CurrentTPS(), ms.Alloc, ms.NumGC))
-	// ebitenutil.DebugPrint(screen, fmt.Sprintf("%v %v", b.bookmark, len(b.undoStack)))
+	// ebitenutil.DebugPrint(screen, fmt.Sprintf("%v %v", b.bookmarks, len(b.undoStack)))
 	// bounds := screen.Bounds()
 	// ebitenutil.DebugPrint(screen, bounds.String())
 	// }
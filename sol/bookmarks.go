@@ -0,0 +1,208 @@
+package sol
+
+import (
+	"fmt"
+	"log"
+
+	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/ui"
+	"oddstream.games/gosol/util"
+)
+
+// The undo stack is the current line of play. Going back to a bookmark (or restarting the deal)
+// doesn't throw away the moves after it; they are kept as a Branch hanging off the current line,
+// so the history is a tree, and the user can go back and forth between the branches.
+
+// Bookmark is a named position in a line of play.
+// Each state on the undo stack carries the bookmarks made before it,
+// so undoing past a bookmark removes it, as it always did
+type Bookmark struct {
+	Name string
+	Len  int // length of the undo stack at the bookmarked position
+}
+
+// Branch is a line of play that isn't the current one.
+// It shares the first Fork states of the undo stack, and carries on with States
+type Branch struct {
+	Fork   int
+	States []*SavableBaize
+}
+
+// rebaseBranches is called before the undo stack is cut down to n states.
+// Branches that fork beyond n would lose the states they share with the undo stack,
+// so they take a copy of them and fork at n instead
+func rebaseBranches(branches []*Branch, undoStack []*SavableBaize, n int) {
+	for _, br := range branches {
+		if br.Fork > n && br.Fork <= len(undoStack) {
+			states := make([]*SavableBaize, 0, br.Fork-n+len(br.States))
+			states = append(states, undoStack[n:br.Fork]...)
+			br.States = append(states, br.States...)
+			br.Fork = n
+		}
+	}
+}
+
+// switchLine makes the first fork states of the undo stack followed by states the current line,
+// and goes to the position length states into it. Whatever is left over of the old line,
+// or the new one, is kept as a branch.
+// Anything but carrying on from the current position goes back over moves, so counts as an undo
+func (b *Baize) switchLine(fork int, states []*SavableBaize, length int) {
+	if fork > len(b.undoStack) {
+		log.Panic("branch forks beyond the end of the undo stack")
+	}
+	if fork < len(b.undoStack) || length < len(b.undoStack) {
+		b.undos++
+	}
+	var line []*SavableBaize
+	line, b.branches = forkLine(b.undoStack, b.branches, fork, states, length)
+	b.SetUndoStack(line)
+	// the branches aren't in the journal, so save them now
+	b.unsaved = true
+	b.Autosave()
+}
+
+// forkLine is the part of switchLine that doesn't touch the Baize;
+// it returns the new line, and the branches with the leftovers of the old and new lines added
+func forkLine(old []*SavableBaize, branches []*Branch, fork int, states []*SavableBaize, length int) ([]*SavableBaize, []*Branch) {
+	rebaseBranches(branches, old, fork)
+	if len(old) > fork {
+		branches = append(branches, &Branch{Fork: fork, States: old[fork:]})
+	}
+	// always build a new slice, so pushing onto the undo stack doesn't scribble over a branch
+	line := make([]*SavableBaize, 0, fork+len(states))
+	line = append(line, old[:fork]...)
+	line = append(line, states...)
+	if length < len(line) {
+		branches = append(branches, &Branch{Fork: length, States: line[length:]})
+	}
+	return line[:length:length], branches
+}
+
+// SavePosition bookmarks the current position
+func (b *Baize) SavePosition() {
+	if b.Complete() {
+		TheUI.Toast("Cannot bookmark a completed game") // otherwise the stats can be cooked
+		sound.Play("Blip")
+		return
+	}
+	if len(b.bookmarks) > 0 && b.bookmarks[len(b.bookmarks)-1].Len == len(b.undoStack) {
+		TheUI.Toast("Position already bookmarked")
+		return
+	}
+	var name string
+	for n := len(b.bookmarks) + 1; ; n++ {
+		name = fmt.Sprintf("Bookmark %d", n)
+		if findBookmark(b.bookmarks, name) == nil {
+			break
+		}
+	}
+	// states further down the undo stack share the old slice, so don't append to it in place
+	b.bookmarks = append(b.bookmarks[:len(b.bookmarks):len(b.bookmarks)], Bookmark{Name: name, Len: len(b.undoStack)})
+	sb := b.UndoPeek()
+	sb.Bookmarks = b.bookmarks
+	sb.Recycles = b.recycles
+	sb.Redeals = b.redeals
+	b.journal(len(b.undoStack)-1, []*SavableBaize{sb})
+	TheUI.Toast(fmt.Sprintf("Position bookmarked as '%s'", name))
+}
+
+func findBookmark(bookmarks []Bookmark, name string) *Bookmark {
+	for i := range bookmarks {
+		if bookmarks[i].Name == name {
+			return &bookmarks[i]
+		}
+	}
+	return nil
+}
+
+// LoadPosition goes back to the most recent bookmark
+func (b *Baize) LoadPosition() {
+	if len(b.bookmarks) == 0 {
+		TheUI.Toast("No bookmark")
+		sound.Play("Blip")
+		return
+	}
+	b.GotoPosition(-1, b.bookmarks[len(b.bookmarks)-1].Len)
+}
+
+// GotoPosition goes to the position length states into a line of play;
+// branch is an index into Baize.branches, or -1 for the current line
+func (b *Baize) GotoPosition(branch int, length int) {
+	if b.Complete() {
+		TheUI.Toast("Cannot leave a completed game") // otherwise the stats can be cooked
+		sound.Play("Blip")
+		return
+	}
+	if branch < 0 {
+		if length < 1 || length > len(b.undoStack) {
+			TheUI.Toast("No such position")
+			sound.Play("Blip")
+			return
+		}
+		b.switchLine(len(b.undoStack), nil, length)
+		return
+	}
+	if branch >= len(b.branches) {
+		TheUI.Toast("No such branch")
+		sound.Play("Blip")
+		return
+	}
+	br := b.branches[branch]
+	if length < 1 || length > br.Fork+len(br.States) || br.Fork > len(b.undoStack) {
+		TheUI.Toast("No such position")
+		sound.Play("Blip")
+		return
+	}
+	b.branches = append(b.branches[:branch], b.branches[branch+1:]...)
+	b.switchLine(br.Fork, br.States, length)
+}
+
+// ShowBookmarksDrawer lists the bookmarks in the current line, then the branches and their bookmarks
+func (b *Baize) ShowBookmarksDrawer() {
+	entries := []ui.ListEntry{{Text: "This line", Detail: fmt.Sprintf("%d moves", len(b.undoStack)-1)}}
+	for _, bm := range b.bookmarks {
+		entries = append(entries, ui.ListEntry{
+			Icon:        "bookmark",
+			Text:        bm.Name,
+			Detail:      fmt.Sprintf("move %d", bm.Len-1),
+			RequestType: "Goto position",
+			Data:        fmt.Sprintf("-1 %d", bm.Len),
+		})
+	}
+	for i, br := range b.branches {
+		if len(br.States) == 0 {
+			continue
+		}
+		length := br.Fork + len(br.States)
+		entries = append(entries, ui.ListEntry{
+			Icon:        "restore",
+			Text:        fmt.Sprintf("Branch %d", i+1),
+			Detail:      fmt.Sprintf("from move %d, %d moves", util.Max(br.Fork-1, 0), length-1),
+			RequestType: "Goto position",
+			Data:        fmt.Sprintf("%d %d", i, length),
+		})
+		// bookmarks before the fork are shared with the current line, and already listed
+		for _, bm := range br.States[len(br.States)-1].Bookmarks {
+			if bm.Len > br.Fork {
+				entries = append(entries, ui.ListEntry{
+					Icon:        "bookmark",
+					Text:        bm.Name,
+					Detail:      fmt.Sprintf("move %d of branch %d", bm.Len-1, i+1),
+					RequestType: "Goto position",
+					Data:        fmt.Sprintf("%d %d", i, bm.Len),
+				})
+			}
+		}
+	}
+	TheUI.ShowListDrawer("Bookmarks", entries)
+}
+
+// gotoPositionRequest decodes the Data of a "Goto position" change request
+func gotoPositionRequest(data string) {
+	var branch, length int
+	if _, err := fmt.Sscanf(data, "%d %d", &branch, &length); err != nil {
+		log.Println("bad position", data, err)
+		return
+	}
+	TheBaize.GotoPosition(branch, length)
+}
@@ -0,0 +1,159 @@
+package sol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// each state is told apart by its Bookmark field, which is otherwise only used by old saves
+
+func states(ids ...int) []*SavableBaize {
+	var ss []*SavableBaize
+	for _, id := range ids {
+		ss = append(ss, &SavableBaize{Bookmark: id})
+	}
+	return ss
+}
+
+func stateIDs(ss []*SavableBaize) []int {
+	ids := []int{}
+	for _, s := range ss {
+		ids = append(ids, s.Bookmark)
+	}
+	return ids
+}
+
+// branchLines returns the whole line of play that each branch sees, from the start of the deal
+func branchLines(undoStack []*SavableBaize, branches []*Branch) [][]int {
+	lines := [][]int{}
+	for _, br := range branches {
+		lines = append(lines, append(stateIDs(undoStack[:br.Fork]), stateIDs(br.States)...))
+	}
+	return lines
+}
+
+// gotoLine does what Baize.GotoPosition does to the undo stack and branches
+func gotoLine(undoStack []*SavableBaize, branches []*Branch, branch, length int) ([]*SavableBaize, []*Branch) {
+	if branch < 0 {
+		return forkLine(undoStack, branches, len(undoStack), nil, length)
+	}
+	br := branches[branch]
+	branches = append(branches[:branch], branches[branch+1:]...)
+	return forkLine(undoStack, branches, br.Fork, br.States, length)
+}
+
+func TestRebaseBranches(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		branches []*Branch
+		n        int   // undo until there are this many states
+		push     []int // then make these moves
+		want     [][]int
+	}{
+		{"fork before the undo", []*Branch{{2, states(20, 21)}}, 3, []int{30}, [][]int{{1, 2, 20, 21}}},
+		{"fork at the undo", []*Branch{{3, states(30)}}, 3, []int{31, 32}, [][]int{{1, 2, 3, 30}}},
+		{"undo past a fork", []*Branch{{4, states(40, 41)}}, 2, []int{30, 31, 32}, [][]int{{1, 2, 3, 4, 40, 41}}},
+		{"undo past the end of a branch", []*Branch{{5, states()}}, 1, []int{20}, [][]int{{1, 2, 3, 4, 5}}},
+		{"several forks", []*Branch{{2, states(20)}, {4, states(40)}, {5, states(50)}}, 3, []int{30, 31},
+			[][]int{{1, 2, 20}, {1, 2, 3, 4, 40}, {1, 2, 3, 4, 5, 50}}},
+	} {
+		b := &Baize{undoStack: states(1, 2, 3, 4, 5), branches: tc.branches}
+		for len(b.undoStack) > tc.n {
+			b.UndoPop()
+		}
+		// moves are pushed onto the undo stack in place, over the states that were undone
+		b.undoStack = append(b.undoStack, states(tc.push...)...)
+		if got := branchLines(b.undoStack, b.branches); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: branches see %v, wanted %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSwitchLine(t *testing.T) {
+	type step struct {
+		undo           int   // undo this many moves first
+		push           []int // then make these moves
+		branch, length int   // then go to this position
+	}
+	for _, tc := range []struct {
+		name     string
+		branches []*Branch
+		steps    []step
+		line     []int
+		want     [][]int
+	}{
+		{"restart deal", nil, []step{{0, nil, -1, 1}}, []int{1}, [][]int{{1, 2, 3, 4, 5}}},
+		{"back to a bookmark", nil, []step{{0, nil, -1, 3}}, []int{1, 2, 3}, [][]int{{1, 2, 3, 4, 5}}},
+		{"back to a bookmark, then new moves", nil, []step{{0, nil, -1, 3}, {0, []int{30}, -1, 4}},
+			[]int{1, 2, 3, 30}, [][]int{{1, 2, 3, 4, 5}}},
+		{"into a branch", []*Branch{{2, states(20, 21)}}, []step{{0, nil, 0, 4}},
+			[]int{1, 2, 20, 21}, [][]int{{1, 2, 3, 4, 5}}},
+		{"into a branch and back", []*Branch{{2, states(20, 21)}}, []step{{0, nil, 0, 4}, {0, nil, 0, 5}},
+			[]int{1, 2, 3, 4, 5}, [][]int{{1, 2, 20, 21}}},
+		{"to a bookmark in a branch", []*Branch{{2, states(20, 21, 22)}}, []step{{0, nil, 0, 3}},
+			[]int{1, 2, 20}, [][]int{{1, 2, 3, 4, 5}, {1, 2, 20, 21, 22}}},
+		{"into a branch, new moves, and back", []*Branch{{2, states(20, 21)}}, []step{{0, nil, 0, 3}, {0, []int{30}, 0, 5}},
+			[]int{1, 2, 3, 4, 5}, [][]int{{1, 2, 20, 21}, {1, 2, 20, 30}}},
+		{"undo past a fork, then into the branch", []*Branch{{4, states(40)}}, []step{{3, []int{30}, 0, 5}},
+			[]int{1, 2, 3, 4, 40}, [][]int{{1, 2, 30}}},
+	} {
+		b := &Baize{undoStack: states(1, 2, 3, 4, 5), branches: tc.branches}
+		for _, st := range tc.steps {
+			for i := 0; i < st.undo; i++ {
+				b.UndoPop()
+			}
+			b.undoStack = append(b.undoStack, states(st.push...)...)
+			b.undoStack, b.branches = gotoLine(b.undoStack, b.branches, st.branch, st.length)
+		}
+		if got := stateIDs(b.undoStack); !reflect.DeepEqual(got, tc.line) {
+			t.Errorf("%s: line is %v, wanted %v", tc.name, got, tc.line)
+		}
+		if got := branchLines(b.undoStack, b.branches); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: branches see %v, wanted %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReplayJournalKeepsBranches(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		entries []journalEntry
+		line    []int
+		want    [][]int
+	}{
+		{"moves", []journalEntry{{Len: 5, States: states(6)}, {Len: 6, States: states(7)}},
+			[]int{1, 2, 3, 4, 5, 6, 7}, [][]int{{1, 2, 20}, {1, 2, 3, 4, 40}}},
+		{"an undo past a fork", []journalEntry{{Len: 3, States: states(30)}},
+			[]int{1, 2, 3, 30}, [][]int{{1, 2, 20}, {1, 2, 3, 4, 40}}},
+		{"undos past both forks", []journalEntry{{Len: 3, States: states(30)}, {Len: 1, States: states(10)}, {Len: 2, States: states(11)}},
+			[]int{1, 10, 11}, [][]int{{1, 2, 20}, {1, 2, 3, 4, 40}}},
+		// switchLine saves a snapshot after a whole new undo stack, so the branches are not lost
+		{"a whole new undo stack", []journalEntry{{Len: 0, States: states(1, 2, 20)}},
+			[]int{1, 2, 20}, [][]int{}},
+	} {
+		snapshot := []*SavedGame{{
+			Version:   savedGameVersion,
+			Variant:   "Freecell",
+			UndoStack: states(1, 2, 3, 4, 5),
+			Branches:  []*Branch{{2, states(20)}, {4, states(40)}},
+		}}
+		var journal []byte
+		for i, je := range tc.entries {
+			je.Seq, je.Variant = int64(i+2), "Freecell"
+			bytes, _ := json.Marshal(je)
+			journal = append(journal, append(bytes, '\n')...)
+		}
+		games, _ := replayJournal(snapshot, 1, journal)
+		if len(games) != 1 {
+			t.Fatalf("%s: wrong number of games: %d", tc.name, len(games))
+		}
+		sg := games[0]
+		if got := stateIDs(sg.UndoStack); !reflect.DeepEqual(got, tc.line) {
+			t.Errorf("%s: line is %v, wanted %v", tc.name, got, tc.line)
+		}
+		if got := branchLines(sg.UndoStack, sg.Branches); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: branches see %v, wanted %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -333,7 +333,7 @@ func (c *Card) Draw(screen *ebiten.Image) {
 	if c.flipStep < 0 {
 		if c.Prone() {
 			// card is getting narrower, and it's going to show face down, but show face up
-			img = TheCardFaceImageLibrary[(c.Suit()*13)+(c.Ordinal()-1)]
+			img = c.faceImage()
 		} else {
 			// card is getting narrower, and it's going to show face up, but show face down
 			img = CardBackImage
@@ -342,7 +342,7 @@ func (c *Card) Draw(screen *ebiten.Image) {
 		if c.Prone() {
 			img = CardBackImage
 		} else {
-			img = TheCardFaceImageLibrary[(c.Suit()*13)+(c.Ordinal()-1)]
+			img = c.faceImage()
 		}
 	}
 
@@ -31,6 +31,7 @@ const (
 	ordinalMask CardID = 0b0000000000001111
 	proneFlag   CardID = 0b0001000000000000
 	jokerFlag   CardID = 0b0010000000000000
+	jokerMask   CardID = 0b1100000000000000 // which joker this is, when there's more than one in a pack
 )
 
 func (cid CardID) String() string {
@@ -122,16 +123,38 @@ func (c *Card) SetProne(prone bool) {
 	}
 }
 
-// Prone returns the joker flag buried in the card id
+// Joker returns the joker flag buried in the card id
 func (cid CardID) Joker() bool {
 	return cid&jokerFlag == jokerFlag
 }
 
-// Prone returns true if the card is face down, false if it is face up
+// Joker returns true if the card is a joker, even if it is pretending to be some other card
 func (c *Card) Joker() bool {
 	return c.ID.Joker()
 }
 
+// Wild returns true if the card is a joker that is not (yet) pretending to be some other card
+func (cid CardID) Wild() bool {
+	return cid.Joker() && cid.Ordinal() == 0
+}
+
+// Wild returns true if the card is a joker that is not (yet) pretending to be some other card
+func (c *Card) Wild() bool {
+	return c.ID.Wild()
+}
+
+// Pretend makes a joker take on the suit and ordinal of another card
+func (c *Card) Pretend(suit, ordinal int) {
+	c.ID = c.ID&^(suitMask|ordinalMask) | NewCardID(0, suit, ordinal)&(suitMask|ordinalMask)
+}
+
+// ResetJoker makes a joker go back to being wild
+func (c *Card) ResetJoker() {
+	if c.Joker() {
+		c.ID = c.ID &^ (suitMask | ordinalMask)
+	}
+}
+
 // Color returns Red or Black
 func (cid CardID) Color() color.RGBA {
 	suit := cid.Suit()
@@ -187,14 +210,26 @@ func NewCardID(pack, suit, ordinal int) CardID {
 	return CardID(u)
 }
 
+// NewJokerID constructor, index tells apart the jokers in the same pack
+func NewJokerID(pack, index int) CardID {
+	return NewCardID(pack, NOSUIT, 0) | (CardID(index)<<14)&jokerMask
+}
+
 // SameCard returns true if the two cards have the same ordinal and suit; pack is ignored
 func SameCard(ID1, ID2 CardID) bool {
 	return ID1&(suitMask|ordinalMask) == ID2&(suitMask|ordinalMask)
 }
 
-// SameCardAndPack returns true if the two card IDs have the same ordinal and suit, and are from the same pack
+// SameCardAndPack returns true if the two card IDs have the same ordinal and suit, and are from the same pack;
+// a joker pretending to be a card is not the same as that card, and two jokers in the same pack are not the same
 func SameCardAndPack(ID1, ID2 CardID) bool {
-	return ID1&(packMask|suitMask|ordinalMask) == ID2&(packMask|suitMask|ordinalMask)
+	const mask = packMask | suitMask | ordinalMask | jokerFlag | jokerMask
+	return ID1&mask == ID2&mask
+}
+
+// SameJoker returns true if the two card IDs are the same joker, whatever they are pretending to be
+func SameJoker(ID1, ID2 CardID) bool {
+	return ID1.Joker() && ID1&(packMask|jokerFlag|jokerMask) == ID2&(packMask|jokerFlag|jokerMask)
 }
 
 // SuitStringToInt converts a suit string ("Heart") to an int (HEART)
@@ -2,6 +2,7 @@ package sol
 
 import (
 	"fmt"
+	"image"
 	"testing"
 )
 
@@ -24,3 +25,55 @@ func TestCardID(t *testing.T) {
 		t.Errorf("cards should NOT be the same: %s and %s", cid, cid2)
 	}
 }
+
+func TestJokerID(t *testing.T) {
+	j0 := NewJokerID(0, 0)
+	j1 := NewJokerID(0, 1)
+	if !j0.Joker() || !j0.Wild() {
+		t.Errorf("should be a wild joker: %s", j0)
+	}
+	if SameJoker(j0, j1) || SameCardAndPack(j0, j1) {
+		t.Errorf("jokers should NOT be the same: %s and %s", j0, j1)
+	}
+
+	c := Card{ID: j1}
+	c.Pretend(HEART, 7)
+	if c.Wild() || c.Suit() != HEART || c.Ordinal() != 7 {
+		t.Errorf("joker should be pretending to be 7 of hearts: %s", c.ID)
+	}
+	if !SameJoker(c.ID, j1) {
+		t.Errorf("pretending joker should still be the same joker: %s and %s", c.ID, j1)
+	}
+	if SameCardAndPack(c.ID, NewCardID(0, HEART, 7)) {
+		t.Errorf("pretending joker should NOT be the same as the real card: %s", c.ID)
+	}
+	c.ResetJoker()
+	if !c.Wild() {
+		t.Errorf("joker should be wild again: %s", c.ID)
+	}
+}
+
+func TestJokersSurviveUndo(t *testing.T) {
+	TheBaize = &Baize{}
+	NoCardLerp, NoCardFlip = true, true
+	CreateCardLibrary(1, 4, nil, 2)
+	j0, j1 := &CardLibrary[52], &CardLibrary[53]
+	if !j0.Wild() || !j1.Wild() {
+		t.Fatalf("last two cards should be wild jokers: %s and %s", j0.ID, j1.ID)
+	}
+
+	cell := NewCell(image.Point{})
+	cell.Push(j1)
+	cell.Push(j0)
+	j1.FlipDown()
+	sp := cell.Savable()
+
+	cell.UpdateFromSavable(sp)
+	cards := cell.Cards()
+	if len(cards) != 2 || cards[0] != j1 || cards[1] != j0 {
+		t.Fatalf("jokers rebuilt incorrectly: %v", cards)
+	}
+	if cards[0].ID != sp.Cards[0] || cards[1].ID != sp.Cards[1] {
+		t.Errorf("jokers changed identity: %s, %s", cards[0].ID, cards[1].ID)
+	}
+}
@@ -95,6 +95,47 @@ var Pips [13][]PipInfo = [13][]PipInfo{
 	{},
 }
 
+const JOKER_RUNE = rune(9733) // 0x2605 black star
+
+// jokerFaceImages are made when first needed, because most variants don't have jokers
+var jokerFaceImages = map[CardID]*ebiten.Image{}
+
+// faceImage returns the face image for this card, including whatever a joker is pretending to be
+func (c *Card) faceImage() *ebiten.Image {
+	if c.Joker() {
+		var key CardID = c.ID & (jokerFlag | suitMask | ordinalMask)
+		img, ok := jokerFaceImages[key]
+		if !ok {
+			img = createFaceImage(key)
+			jokerFaceImages[key] = img
+		}
+		return img
+	}
+	return TheCardFaceImageLibrary[(c.Suit()*13)+(c.Ordinal()-1)]
+}
+
+// createWildFaceImage draws a joker that isn't pretending to be anything, with stars where the ordinals and suits would be
+func createWildFaceImage(dc *gg.Context) {
+	w := float64(CardWidth)
+	h := float64(CardHeight)
+
+	dc.SetColor(BasicColors["Purple"])
+	dc.SetFontFace(schriftbank.CardSymbolSmall)
+	dc.DrawStringAnchored(string(JOKER_RUNE), w*CTLX, h*CTLY, 0.5, 0.4)
+	dc.DrawStringAnchored(string(JOKER_RUNE), w*CTRX, h*CTRY, 0.5, 0.4)
+	dc.DrawStringAnchored(string(JOKER_RUNE), w*CBLX, h*CBLY, 0.5, 0.4)
+	dc.DrawStringAnchored(string(JOKER_RUNE), w*CBRX, h*CBRY, 0.5, 0.4)
+
+	dc.SetRGBA(0, 0, 0, 0.05)
+	dc.DrawRectangle(w*0.25, h*0.25, w*0.5, h*0.5)
+	dc.Fill()
+
+	dc.SetColor(BasicColors["Purple"])
+	dc.SetFontFace(schriftbank.CardSymbolLarge)
+	dc.DrawStringAnchored(string(JOKER_RUNE), w*0.5, h*0.44, 0.5, 0.5)
+	dc.Stroke()
+}
+
 // createFaceImage tries to draw an image for this card that looks like kenney.nl playingCards.png
 func createFaceImage(ID CardID) *ebiten.Image {
 	w := float64(CardWidth)
@@ -115,6 +156,11 @@ func createFaceImage(ID CardID) *ebiten.Image {
 	dc.DrawRoundedRectangle(1, 1, w-2, h-2, CardCornerRadius)
 	dc.Stroke() // otherwise outline gets drawn in textColor (!?)
 
+	if ID.Wild() {
+		createWildFaceImage(dc)
+		return ebiten.NewImageFromImage(dc.Image())
+	}
+
 	var cardOrdinal = ID.Ordinal()
 	var suitRune rune = ID.SuitRune()
 	var cardColor color.RGBA = ID.Color()
@@ -236,6 +282,9 @@ func CreateCardShadowImage() *ebiten.Image {
 func CreateCardFaceImageLibrary() {
 	defer util.Duration(time.Now(), "CreateCardFaceImageLibrary")
 
+	// card size or face color may have changed, so the jokers will have to be redrawn too
+	jokerFaceImages = map[CardID]*ebiten.Image{}
+
 	for _, suit := range []int{NOSUIT, CLUB, DIAMOND, HEART, SPADE} {
 		for ord := 1; ord < 14; ord++ {
 			ID := NewCardID(0, suit, ord)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"oddstream.games/gosol/sound"
@@ -18,8 +19,12 @@ var CommandTable = map[ebiten.Key]func(){
 	ebiten.KeyU: func() { TheBaize.Undo() },
 	ebiten.KeyS: func() { TheBaize.SavePosition() },
 	ebiten.KeyL: func() { TheBaize.LoadPosition() },
+	ebiten.KeyB: func() { TheBaize.ShowBookmarksDrawer() },
+	ebiten.KeyT: func() { TheBaize.ToggleTimeline() },
 	ebiten.KeyC: func() { TheBaize.Collect() },
 	ebiten.KeyF: func() { TheBaize.ShowVariantGroupPicker() },
+	ebiten.KeyD: func() { ShowDailyDrawer() },
+	ebiten.KeyP: func() { ShowProfilesDrawer() },
 	ebiten.KeyM: func() { ThePreferences.MarkMovableCards = !ThePreferences.MarkMovableCards },
 	ebiten.KeyX: func() { ExitRequested = true },
 	ebiten.KeyTab: func() {
@@ -31,11 +36,14 @@ var CommandTable = map[ebiten.Key]func(){
 		}
 	},
 	ebiten.KeyF1:     func() { TheBaize.Wikipedia() },
-	ebiten.KeyF2:     func() { TheStatistics.WelcomeToast(TheBaize.LongVariantName()) },
+	ebiten.KeyF2:     func() { ShowStatisticsDrawer() },
 	ebiten.KeyF3:     func() { ShowSettingsDrawer() },
+	ebiten.KeyF4:     func() { ShowCustomDrawer() },
 	ebiten.KeyF5:     func() { TheBaize.StartSpinning() },
 	ebiten.KeyF6:     func() { TheBaize.StopSpinning() },
+	ebiten.KeyF7:     func() { slotDeletePending = ""; ShowSlotsDrawer() },
 	ebiten.KeyF8:     func() { TheUI.HideFAB() },
+	ebiten.KeyF9:     func() { ShowAchievementsDrawer() },
 	ebiten.KeyMenu:   func() { TheUI.ToggleNavDrawer() },
 	ebiten.KeyEscape: func() { TheUI.HideActiveDrawer() },
 }
@@ -50,6 +58,44 @@ func Execute(cmd interface{}) {
 		}
 
 	case ui.ChangeRequest:
+		// the custom variant drawer stays open while it's settings are changed
+		if strings.HasPrefix(v.ChangeRequested, "Custom ") {
+			customChangeRequest(strings.TrimPrefix(v.ChangeRequested, "Custom "), v.Data)
+			return
+		}
+		if v.ChangeRequested == "Custom" {
+			customCommand(v.Data)
+			return
+		}
+		// the timeline sends a stream of these as it's dragged, so don't close anything or save the preferences
+		if strings.HasPrefix(v.ChangeRequested, "Timeline ") {
+			timelineCommand(v.ChangeRequested, v.Data)
+			return
+		}
+		// the statistics drawer stays open, going between the table and the details of a variant
+		if strings.HasPrefix(v.ChangeRequested, "Statistics") {
+			statisticsCommand(v.ChangeRequested, v.Data)
+			return
+		}
+		// the saved games drawer stays open while a name is typed, or a game is deleted
+		if v.ChangeRequested == "Slot name" || strings.HasPrefix(v.ChangeRequested, "Delete slot") {
+			slotsCommand(v.ChangeRequested, v.Data)
+			return
+		}
+		// the profiles drawer stays open while a name is typed
+		if v.ChangeRequested == "Profile name" {
+			profileName = v.Data
+			return
+		}
+		// the settings drawer stays open, so the scoring spinner can be tapped through it's choices
+		if v.ChangeRequested == "Scoring" {
+			ThePreferences.Scoring = v.Data
+			ThePreferences.Save()
+			if v.Data != TheBaize.scoring {
+				TheUI.Toast("The new scoring starts with the next deal")
+			}
+			return
+		}
 		// a widget has sent a change request
 		TheUI.HideActiveDrawer()
 		TheUI.HideFAB()
@@ -78,9 +124,24 @@ func Execute(cmd interface{}) {
 			TheBaize.setFlag(dirtyCardImages)
 		case "Mirror baize":
 			ThePreferences.MirrorBaize, _ = strconv.ParseBool(v.Data)
-			savedUndoStack := TheBaize.undoStack
+			savedUndoStack, savedBranches := TheBaize.undoStack, TheBaize.branches
 			TheBaize.StartFreshGame()
 			TheBaize.SetUndoStack(savedUndoStack)
+			TheBaize.branches = savedBranches
+		case "Goto position":
+			gotoPositionRequest(v.Data)
+		case "Profile":
+			SwitchProfile(v.Data)
+		case "New profile":
+			NewProfile()
+		case "Daily play":
+			TheBaize.PlayDaily()
+		case "Daily share":
+			ShareDaily()
+		case "Save slot":
+			TheBaize.SaveSlot()
+		case "Load slot":
+			TheBaize.LoadSlot(v.Data)
 		case "Mute sounds":
 			ThePreferences.Mute, _ = strconv.ParseBool(v.Data)
 			if ThePreferences.Mute {
@@ -80,6 +80,9 @@ type Core struct {
 	symbol rune
 	img    *ebiten.Image
 	target bool // experimental, might delete later, IDK
+	// set by scripts for piles that don't follow the usual rules
+	capacity     int             // number of cards (not counting jokers) that fill this pile, 0 for the default
+	completeFunc func(Pile) bool // replaces the pile's own idea of when it is complete
 }
 
 func NewCore(category string, slot image.Point, fanType FanType, moveType MoveType) Core {
@@ -110,6 +113,11 @@ func (self *Core) Hidden() bool {
 	return self.slot.X < 0 || self.slot.Y < 0
 }
 
+// Category returns the kind of pile this is, eg "Tableau"
+func (self *Core) Category() string {
+	return self.category
+}
+
 func (self *Core) IsStock() bool {
 	return self.category == "Stock"
 }
@@ -318,7 +326,7 @@ func (self *Core) FannedBaizeRect() image.Rectangle {
 		case FAN_RIGHT, FAN_RIGHT3:
 			r.Max.X = cPos.X + CardWidth
 		case FAN_LEFT, FAN_LEFT3:
-			r.Max.X = cPos.X - CardWidth
+			r.Min.X = cPos.X
 		case FAN_DOWN, FAN_DOWN3:
 			r.Max.Y = cPos.Y + CardHeight
 		}
@@ -591,6 +599,31 @@ func (self *Core) Collect() {
 	}
 }
 
+// Capacity returns the number of cards, not counting jokers, that fill this pile;
+// zero means there is no limit (but a Cell without a capacity holds one card)
+func (self *Core) Capacity() int {
+	return self.capacity
+}
+
+// SetCapacity lets a script give a pile a different size to the others of it's kind
+// (eg a Foundation that only holds one rank, or a Cell that holds more than one card).
+// Foundations, Discards, Tableaux and Cells keep to it; the other piles only get the cards
+// the script deals them, so it means nothing to them
+func (self *Core) SetCapacity(n int) {
+	self.capacity = n
+}
+
+// overCapacity is true if adding tail would put more cards, not counting jokers, on the pile than it's capacity;
+// a pile without a capacity is never over it
+func (self *Core) overCapacity(tail []*Card) bool {
+	return self.capacity > 0 && self.Len()-CountJokers(self.cards)+len(tail)-CountJokers(tail) > self.capacity
+}
+
+// SetCompleteFunc lets a script decide when a pile is complete; nil restores the default
+func (self *Core) SetCompleteFunc(fn func(Pile) bool) {
+	self.completeFunc = fn
+}
+
 func (self *Core) Conformant() bool   { return false }
 func (self *Core) Complete() bool     { return false }
 func (self *Core) UnsortedPairs() int { return 0 }
@@ -0,0 +1,89 @@
+package sol
+
+import (
+	"image"
+	"testing"
+)
+
+// libraryCard finds a card in the library, and turns it face up
+func libraryCard(t *testing.T, suit, ord int) *Card {
+	for i := range CardLibrary {
+		if c := &CardLibrary[i]; !c.Joker() && c.Suit() == suit && c.Ordinal() == ord {
+			c.SetProne(false)
+			return c
+		}
+	}
+	t.Fatalf("no %d of suit %d in the library", ord, suit)
+	return nil
+}
+
+func TestCellCapacity(t *testing.T) {
+	TheBaize = &Baize{}
+	NoCardLerp, NoCardFlip = true, true
+	CreateCardLibrary(1, 4, nil, 1)
+	joker := &CardLibrary[52]
+	joker.SetProne(false)
+	ace, two, three := libraryCard(t, CLUB, 1), libraryCard(t, CLUB, 2), libraryCard(t, CLUB, 3)
+
+	cell := NewCell(image.Point{})
+	cell.Push(ace)
+	if ok, _ := cell.CanAcceptTail([]*Card{two}); ok {
+		t.Error("a Cell without a capacity should only hold one card")
+	}
+
+	cell.SetCapacity(2)
+	for _, tc := range []struct {
+		name string
+		tail []*Card
+		ok   bool
+	}{
+		{"room for one more", []*Card{two}, true},
+		{"a joker doesn't count", []*Card{joker}, true},
+		{"only one card at a time", []*Card{two, three}, false},
+	} {
+		if ok, err := cell.CanAcceptTail(tc.tail); ok != tc.ok {
+			t.Errorf("%s: got %v, %v", tc.name, ok, err)
+		}
+		if ok, err := cell.CanAcceptCard(tc.tail[0]); len(tc.tail) == 1 && ok != tc.ok {
+			t.Errorf("%s: card got %v, %v", tc.name, ok, err)
+		}
+	}
+	cell.Push(two)
+	if ok, _ := cell.CanAcceptTail([]*Card{three}); ok {
+		t.Error("a full Cell should not take another card")
+	}
+}
+
+func TestTableauCapacity(t *testing.T) {
+	TheBaize = &Baize{script: &Freecell{}}
+	NoCardLerp, NoCardFlip = true, true
+	CreateCardLibrary(1, 4, nil, 1)
+	joker := &CardLibrary[52]
+	joker.SetProne(false)
+	nine, eight, seven := libraryCard(t, SPADE, 9), libraryCard(t, HEART, 8), libraryCard(t, CLUB, 7)
+
+	tableau := NewTableau(image.Point{}, FAN_DOWN, MOVE_ANY)
+	tableau.Push(nine)
+	if ok, err := tableau.CanAcceptTail([]*Card{eight, seven}); !ok {
+		t.Errorf("a Tableau without a capacity should take any number of cards: %v", err)
+	}
+
+	tableau.SetCapacity(2)
+	for _, tc := range []struct {
+		name string
+		tail []*Card
+		ok   bool
+	}{
+		{"room for one more", []*Card{eight}, true},
+		{"a joker doesn't count", []*Card{eight, joker}, true},
+		{"over capacity", []*Card{eight, seven}, false},
+	} {
+		if ok, err := tableau.CanAcceptTail(tc.tail); ok != tc.ok {
+			t.Errorf("%s: got %v, %v", tc.name, ok, err)
+		}
+	}
+	tableau.Push(eight)
+	if ok, _ := tableau.CanAcceptCard(seven); ok {
+		t.Error("a full Tableau should not take another card")
+	}
+}
@@ -0,0 +1,412 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"oddstream.games/gosol/ui"
+	"oddstream.games/gosol/util"
+)
+
+// CustomVariant is a user's own version of one of the parameterised variants.
+// It's kept as plain names and numbers, so it can be saved and shared as a small JSON file.
+type CustomVariant struct {
+	Name        string
+	Family      string // "Klondike", "Forty Thieves" or "Spider"
+	Draw        int    `json:",omitempty"`
+	Recycles    int    `json:",omitempty"`
+	Founds      int    `json:",omitempty"`
+	Tabs        int    `json:",omitempty"`
+	CardsPerTab int    `json:",omitempty"`
+	ProneRows   int    `json:",omitempty"`
+	TabCompare  string `json:",omitempty"`
+	MoveType    string `json:",omitempty"`
+	Packs       int    `json:",omitempty"`
+	Suits       int    `json:",omitempty"`
+}
+
+const customGroup = "> Custom"
+
+// CustomVariants are the ones the user has saved, in the order they were made
+var CustomVariants []*CustomVariant
+
+// customDraft is the variant being edited in the custom variant drawer
+var customDraft *CustomVariant
+
+var customFamilies = []string{"Klondike", "Forty Thieves", "Spider"}
+
+var customTabCompares = map[string]func(CardPair) (bool, error){
+	"Down, same suit":      CardPair.Compare_DownSuit,
+	"Down, other suit":     CardPair.Compare_DownOtherSuit,
+	"Down, alt color":      CardPair.Compare_DownAltColor,
+	"Down, any suit":       CardPair.Compare_Down,
+	"Up or down, any suit": CardPair.Compare_UpOrDown,
+}
+
+// the names of the tableau builds, in the order the spinner shows them
+var customTabCompareNames = []string{"Down, same suit", "Down, other suit", "Down, alt color", "Down, any suit", "Up or down, any suit"}
+
+var customMoveTypes = map[string]MoveType{
+	"One card":        MOVE_ONE,
+	"One card, power": MOVE_ONE_PLUS,
+	"Any cards":       MOVE_ANY,
+}
+
+var customMoveTypeNames = []string{"One card", "One card, power", "Any cards"}
+
+// customParam is one thing that can be changed about a custom variant
+type customParam struct {
+	name    string
+	options func(*CustomVariant) []string
+	get     func(*CustomVariant) int
+	set     func(*CustomVariant, int)
+}
+
+// numbers returns the options for a param that is just a range of numbers
+func numbers(from, to int) func(*CustomVariant) []string {
+	return func(*CustomVariant) []string {
+		var opts []string
+		for i := from; i <= to; i++ {
+			opts = append(opts, strconv.Itoa(i))
+		}
+		return opts
+	}
+}
+
+// unlimited recycles are stored as 32767, like Canfield and Thoughtful
+const unlimitedRecycles = 32767
+
+var customParams = map[string][]customParam{
+	"Klondike": {
+		{"Draw", numbers(1, 3), func(cv *CustomVariant) int { return cv.Draw }, func(cv *CustomVariant, n int) { cv.Draw = n }},
+		{"Recycles", func(*CustomVariant) []string { return []string{"0", "1", "2", "3", "9", "Unlimited"} },
+			func(cv *CustomVariant) int { return cv.Recycles }, func(cv *CustomVariant, n int) { cv.Recycles = n }},
+	},
+	"Forty Thieves": {
+		{"Foundations", func(*CustomVariant) []string { return []string{"4", "8", "12"} },
+			func(cv *CustomVariant) int { return cv.Founds }, func(cv *CustomVariant, n int) { cv.Founds = n }},
+		{"Tableaux", numbers(4, 13), func(cv *CustomVariant) int { return cv.Tabs }, func(cv *CustomVariant, n int) { cv.Tabs = n }},
+		{"Cards per tableau", numbers(1, 6), func(cv *CustomVariant) int { return cv.CardsPerTab }, func(cv *CustomVariant, n int) { cv.CardsPerTab = n }},
+		{"Face down rows", numbers(0, 5), func(cv *CustomVariant) int { return cv.ProneRows }, func(cv *CustomVariant, n int) { cv.ProneRows = n }},
+		{"Recycles", numbers(0, 2), func(cv *CustomVariant) int { return cv.Recycles }, func(cv *CustomVariant, n int) { cv.Recycles = n }},
+	},
+	"Spider": {
+		{"Suits", func(*CustomVariant) []string { return []string{"1", "2", "4"} },
+			func(cv *CustomVariant) int { return cv.Suits }, func(cv *CustomVariant, n int) { cv.Suits = n }},
+		// there's a discard for every thirteen cards, so the choice of packs depends on the number of suits
+		{"Packs", func(cv *CustomVariant) []string {
+			return []string{strconv.Itoa(8 / cv.Suits), strconv.Itoa(12 / cv.Suits), strconv.Itoa(16 / cv.Suits)}
+		}, func(cv *CustomVariant) int { return cv.Packs }, func(cv *CustomVariant, n int) { cv.Packs = n }},
+	},
+}
+
+// NewCustomVariant returns a custom variant with the same settings as the standard game of that family
+func NewCustomVariant(family string) *CustomVariant {
+	switch family {
+	case "Forty Thieves":
+		return &CustomVariant{Family: family, Founds: 8, Tabs: 10, CardsPerTab: 4, TabCompare: "Down, same suit", MoveType: "One card, power"}
+	case "Spider":
+		return &CustomVariant{Family: family, Packs: 2, Suits: 4}
+	default:
+		return &CustomVariant{Family: "Klondike", Draw: 1, Recycles: 2}
+	}
+}
+
+// Validate returns an error if the variant can't be dealt.
+// A shared file could hold anything, so numbers are kept to what the custom variant drawer offers
+func (cv *CustomVariant) Validate() error {
+	switch cv.Family {
+	case "Klondike":
+		if cv.Draw < 1 || cv.Draw > 3 {
+			return errors.New("Draw must be one, two or three cards")
+		}
+		if (cv.Recycles < 0 || cv.Recycles > 9) && cv.Recycles != unlimitedRecycles {
+			return errors.New("Recycles must be from none to nine, or unlimited")
+		}
+	case "Forty Thieves":
+		if cv.Founds%4 != 0 || cv.Founds < 4 || cv.Founds > 12 {
+			return errors.New("There must be four, eight or twelve foundations")
+		}
+		if cv.Tabs < 4 || cv.Tabs > 13 {
+			return errors.New("There must be from four to thirteen tableaux")
+		}
+		if cv.CardsPerTab < 1 || cv.CardsPerTab > 6 {
+			return errors.New("There must be from one to six cards in each tableau")
+		}
+		if cv.ProneRows < 0 {
+			return errors.New("Face down rows can't be negative")
+		}
+		if cv.Recycles < 0 || cv.Recycles > 2 {
+			return errors.New("Recycles must be from none to two")
+		}
+		if _, ok := customTabCompares[cv.TabCompare]; !ok {
+			return fmt.Errorf("Unknown tableau build '%s'", cv.TabCompare)
+		}
+		if _, ok := customMoveTypes[cv.MoveType]; !ok {
+			return fmt.Errorf("Unknown move type '%s'", cv.MoveType)
+		}
+		// one pack for every four foundations, and leave a card for the waste
+		if cv.Tabs*cv.CardsPerTab >= cv.Founds/4*52 {
+			return errors.New("Not enough cards to deal that many tableaux")
+		}
+		if cv.ProneRows >= cv.CardsPerTab {
+			return errors.New("The top card of each tableau must be face up")
+		}
+	case "Spider":
+		if cv.Suits != 1 && cv.Suits != 2 && cv.Suits != 4 {
+			return errors.New("Spider must have one, two or four suits")
+		}
+		if n := cv.Packs * cv.Suits; n != 8 && n != 12 && n != 16 {
+			return errors.New("Spider must have eight, twelve or sixteen sets of cards")
+		}
+	default:
+		return fmt.Errorf("Unknown custom family '%s'", cv.Family)
+	}
+	return nil
+}
+
+// Script builds the script that plays this variant
+func (cv *CustomVariant) Script() ScriptInterface {
+	switch cv.Family {
+	case "Forty Thieves":
+		// foundations and tableaux are right aligned, leaving room at the left for stock and waste
+		right := util.Max(3+cv.Founds, cv.Tabs)
+		ft := &FortyThieves{
+			packs:          cv.Founds / 4,
+			cardsPerTab:    cv.CardsPerTab,
+			recycles:       cv.Recycles,
+			moveType:       customMoveTypes[cv.MoveType],
+			tabCompareFunc: customTabCompares[cv.TabCompare],
+		}
+		for x := right - cv.Founds; x < right; x++ {
+			ft.founds = append(ft.founds, x)
+		}
+		for x := right - cv.Tabs; x < right; x++ {
+			ft.tabs = append(ft.tabs, x)
+		}
+		for row := 0; row < cv.ProneRows; row++ {
+			ft.proneRows = append(ft.proneRows, row)
+		}
+		return ft
+	case "Spider":
+		return &Spider{packs: cv.Packs, suits: cv.Suits}
+	default:
+		return &Klondike{draw: cv.Draw, recycles: cv.Recycles}
+	}
+}
+
+// sameAs returns true if two custom variants play the same game, whatever they're called
+func (cv *CustomVariant) sameAs(other *CustomVariant) bool {
+	a, b := *cv, *other
+	a.Name, b.Name = "", ""
+	return a == b
+}
+
+// registerCustomVariant makes a custom variant playable, and pickable from the variant picker
+func registerCustomVariant(cv *CustomVariant) {
+	Variants[cv.Name] = cv.Script()
+	VariantGroups[customGroup] = append(VariantGroups[customGroup], cv.Name)
+	VariantGroups["> All"] = append(VariantGroups["> All"], cv.Name)
+	sort.Strings(VariantGroups["> All"])
+}
+
+// validCustomName is true if the name will do for a variant; names starting with > are groups
+func validCustomName(name string) bool {
+	if name == "" || len([]rune(name)) > 32 || strings.TrimSpace(name) != name || strings.HasPrefix(name, ">") {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// newCustomName makes up a name for a custom variant that isn't already taken
+func newCustomName(family string) string {
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("Custom %s %d", family, n)
+		if _, taken := Variants[name]; !taken {
+			return name
+		}
+	}
+}
+
+// addCustomVariant gives the variant a name (if it doesn't already have one that will do) and registers it.
+// If an identical custom variant already exists, that one is returned instead
+func addCustomVariant(cv *CustomVariant) (*CustomVariant, error) {
+	if err := cv.Validate(); err != nil {
+		return nil, err
+	}
+	for _, existing := range CustomVariants {
+		if existing.sameAs(cv) {
+			return existing, nil
+		}
+	}
+	if _, taken := Variants[cv.Name]; taken || !validCustomName(cv.Name) {
+		cv.Name = newCustomName(cv.Family)
+	}
+	CustomVariants = append(CustomVariants, cv)
+	registerCustomVariant(cv)
+	saveCustomVariants()
+	return cv, nil
+}
+
+// LoadCustomVariants loads and registers the user's custom variants
+func LoadCustomVariants() {
+	for _, cv := range loadCustomVariants() {
+		if err := cv.Validate(); err != nil {
+			println("ignoring custom variant", cv.Name, err.Error())
+			continue
+		}
+		if _, taken := Variants[cv.Name]; taken {
+			println("ignoring custom variant", cv.Name, "because that name is taken")
+			continue
+		}
+		CustomVariants = append(CustomVariants, cv)
+		registerCustomVariant(cv)
+	}
+}
+
+// findCustomVariant returns the custom variant with this name, or nil
+func findCustomVariant(name string) *CustomVariant {
+	for _, cv := range CustomVariants {
+		if cv.Name == name {
+			return cv
+		}
+	}
+	return nil
+}
+
+// ShowCustomDrawer opens the custom variant drawer, starting with the current variant if it's a custom one
+func ShowCustomDrawer() {
+	if cv := findCustomVariant(ThePreferences.Variant); cv != nil {
+		draft := *cv
+		// a changed copy is a new variant, so needs a new name; an unchanged one is saved as the same variant anyway
+		draft.Name = ""
+		customDraft = &draft
+	} else if customDraft == nil {
+		customDraft = NewCustomVariant("Klondike")
+	}
+	showCustomDraft()
+}
+
+func showCustomDraft() {
+	var settings = []ui.SpinnerSetting{
+		{Text: "Family", Options: customFamilies, Current: customDraft.Family, RequestType: "Custom Family"},
+	}
+	for _, p := range customParams[customDraft.Family] {
+		current := strconv.Itoa(p.get(customDraft))
+		if p.name == "Recycles" && p.get(customDraft) == unlimitedRecycles {
+			current = "Unlimited"
+		}
+		settings = append(settings, ui.SpinnerSetting{Text: p.name, Options: p.options(customDraft), Current: current, RequestType: "Custom " + p.name})
+	}
+	if customDraft.Family == "Forty Thieves" {
+		settings = append(settings,
+			ui.SpinnerSetting{Text: "Build tableaux", Options: customTabCompareNames, Current: customDraft.TabCompare, RequestType: "Custom Build tableaux"},
+			ui.SpinnerSetting{Text: "Move", Options: customMoveTypeNames, Current: customDraft.MoveType, RequestType: "Custom Move"})
+	}
+	TheUI.ShowCustomDrawer(customDraft.Name, newCustomName(customDraft.Family), settings)
+}
+
+// change returns the variant with one of it's settings changed; changing the family starts again
+func (cv *CustomVariant) change(param, value string) *CustomVariant {
+	switch param {
+	case "Family":
+		fresh := NewCustomVariant(value)
+		fresh.Name = cv.Name
+		return fresh
+	case "Build tableaux":
+		cv.TabCompare = value
+	case "Move":
+		cv.MoveType = value
+	default:
+		// there's a discard for every set of thirteen cards, which is packs times suits
+		discards := cv.Packs * cv.Suits
+		for _, p := range customParams[cv.Family] {
+			if p.name != param {
+				continue
+			}
+			if value == "Unlimited" {
+				p.set(cv, unlimitedRecycles)
+			} else if n, err := strconv.Atoi(value); err == nil {
+				p.set(cv, n)
+			}
+		}
+		if param == "Suits" {
+			// keep the same number of discards; there are always eight, twelve or sixteen, which any number of suits divides
+			cv.Packs = discards / cv.Suits
+		}
+	}
+	return cv
+}
+
+// customChangeRequest handles a change from one of the spinners (or the name) in the custom variant drawer
+func customChangeRequest(param, value string) {
+	if param == "Name" {
+		// sent as each letter is typed, so don't rebuild the drawer from under it
+		customDraft.Name = strings.TrimSpace(value)
+		return
+	}
+	customDraft = customDraft.change(param, value)
+	// the list of options may have changed
+	showCustomDraft()
+}
+
+// customCommand handles the buttons at the bottom of the custom variant drawer
+func customCommand(cmd string) {
+	switch cmd {
+	case "Save and play":
+		name := customDraft.Name
+		if name != "" {
+			if !validCustomName(name) {
+				TheUI.Toast(fmt.Sprintf("'%s' won't do as a name", name))
+				return
+			}
+			if _, taken := Variants[name]; taken {
+				// unless it's this one, saved again
+				if existing := findCustomVariant(name); existing == nil || !existing.sameAs(customDraft) {
+					TheUI.Toast(fmt.Sprintf("There is already a variant called '%s'", name))
+					return
+				}
+			}
+		}
+		cv, err := addCustomVariant(customDraft)
+		if err != nil {
+			TheUI.Toast(err.Error())
+			return
+		}
+		if name != "" && cv.Name != name {
+			TheUI.Toast(fmt.Sprintf("That's the same as '%s'", cv.Name))
+		}
+		customDraft = nil
+		TheUI.HideActiveDrawer()
+		if cv.Name != ThePreferences.Variant {
+			TheBaize.ChangeVariant(cv.Name)
+			ThePreferences.Save()
+		}
+	case "Share":
+		if err := customDraft.Validate(); err != nil {
+			TheUI.Toast(err.Error())
+			return
+		}
+		share := *customDraft
+		for _, existing := range CustomVariants {
+			if existing.sameAs(&share) {
+				share.Name = existing.Name
+			}
+		}
+		if share.Name == "" {
+			share.Name = "Custom " + share.Family
+		}
+		shareCustomVariant(&share)
+	}
+}
@@ -0,0 +1,39 @@
+package sol
+
+import "testing"
+
+func TestCustomVariantValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cv   CustomVariant
+		ok   bool
+	}{
+		{"standard Klondike", *NewCustomVariant("Klondike"), true},
+		{"unlimited recycles", CustomVariant{Family: "Klondike", Draw: 3, Recycles: unlimitedRecycles}, true},
+		{"too many recycles", CustomVariant{Family: "Klondike", Draw: 1, Recycles: 1000}, false},
+		{"negative recycles", CustomVariant{Family: "Klondike", Draw: 1, Recycles: -1}, false},
+		{"standard Forty Thieves", *NewCustomVariant("Forty Thieves"), true},
+		{"too many tableaux", CustomVariant{Family: "Forty Thieves", Founds: 12, Tabs: 1000, CardsPerTab: 1, TabCompare: "Down, same suit", MoveType: "One card"}, false},
+		{"too many cards per tableau", CustomVariant{Family: "Forty Thieves", Founds: 12, Tabs: 4, CardsPerTab: 20, TabCompare: "Down, same suit", MoveType: "One card"}, false},
+		{"negative face down rows", CustomVariant{Family: "Forty Thieves", Founds: 8, Tabs: 10, CardsPerTab: 4, ProneRows: -1, TabCompare: "Down, same suit", MoveType: "One card"}, false},
+		{"standard Spider", *NewCustomVariant("Spider"), true},
+		{"Spider with six sets", CustomVariant{Family: "Spider", Packs: 3, Suits: 2}, false},
+	} {
+		if err := tc.cv.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%s: got %v", tc.name, err)
+		}
+	}
+}
+
+func TestCustomSuitsKeepDiscards(t *testing.T) {
+	cv := &CustomVariant{Family: "Spider", Packs: 3, Suits: 4} // twelve discards
+	for _, suits := range []string{"2", "1", "4"} {
+		cv = cv.change("Suits", suits)
+		if cv.Packs*cv.Suits != 12 {
+			t.Errorf("%s suits: %d packs make %d discards, not twelve", suits, cv.Packs, cv.Packs*cv.Suits)
+		}
+	}
+	if cv = cv.change("Family", "Klondike"); cv.Family != "Klondike" || cv.Draw != 1 {
+		t.Errorf("changing family should start again: %+v", cv)
+	}
+}
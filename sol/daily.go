@@ -0,0 +1,270 @@
+package sol
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/ui"
+	"oddstream.games/gosol/util"
+)
+
+// The daily challenge is the same deal, of the same variant, for everyone on the same day.
+// Both are worked out from the date, so there's no need for a server: the variant goes round dailyVariants,
+// and the seed of the shuffle is the date with dailySeedFlag set, which can't be a seed made from the time.
+// That means a daily game can be recognised by its seed, which is already saved with every game.
+// The results are kept in daily.json, and don't count towards the ordinary statistics
+
+const (
+	dailySeedFlag = int64(1) << 40
+	dailyLayout   = "2006-01-02"
+)
+
+// dailyVariants take turns to be the daily challenge; only add to the end, or everyone's challenges will change
+var dailyVariants = []string{
+	"Klondike", "Freecell", "Spider One Suit", "Yukon", "Forty Thieves", "Canfield", "Baker's Dozen",
+	"Klondike Draw Three", "Spider Two Suits", "Penguin", "Scorpion", "Australian", "Beleaguered Castle", "Eight Off",
+}
+
+// dailyEpoch is the day of the first daily challenge
+var dailyEpoch = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// DailyResult is how the daily challenge went on one day
+type DailyResult struct {
+	Variant string
+	Result  string // "won" or "lost"
+	Seconds int    `json:",omitempty"`
+	Moves   int    `json:",omitempty"`
+	Percent int
+}
+
+func today() string {
+	return time.Now().Format(dailyLayout)
+}
+
+func dailySeed(date string) int64 {
+	t, err := time.Parse(dailyLayout, date)
+	if err != nil {
+		log.Panic("bad daily challenge date ", date)
+	}
+	return dailySeedFlag | int64(t.Year()*10000+int(t.Month())*100+t.Day())
+}
+
+// dailyDate returns the date of the daily challenge dealt with the seed, if it was one
+func dailyDate(seed int64) (string, bool) {
+	if seed&dailySeedFlag == 0 {
+		return "", false
+	}
+	n := int(seed &^ dailySeedFlag)
+	t := time.Date(n/10000, time.Month(n/100%100), n%100, 0, 0, 0, 0, time.UTC)
+	return t.Format(dailyLayout), true
+}
+
+func dailyVariant(date string) string {
+	t, err := time.Parse(dailyLayout, date)
+	if err != nil {
+		log.Panic("bad daily challenge date ", date)
+	}
+	days := int(t.Sub(dailyEpoch).Hours() / 24)
+	n := len(dailyVariants)
+	return dailyVariants[((days%n)+n)%n]
+}
+
+func loadDailyResults() map[string]*DailyResult {
+	results := make(map[string]*DailyResult)
+	loadData("daily.json", func(bytes []byte) error { return json.Unmarshal(bytes, &results) })
+	return results
+}
+
+func saveDailyResults(results map[string]*DailyResult) {
+	bytes, err := json.MarshalIndent(results, "", "\t")
+	if err != nil {
+		log.Println("saveDailyResults Marshal", err)
+		return
+	}
+	saveData("daily.json", bytes)
+}
+
+// recordDailyResult records the daily challenge that has just finished.
+// It can be played again, but a win is never replaced, so the first win is the one that counts
+func recordDailyResult(date string, result string) {
+	results := loadDailyResults()
+	if old, ok := results[date]; ok && old.Result == "won" {
+		return
+	}
+	gr := TheBaize.gameRecord(result)
+	results[date] = &DailyResult{Variant: gr.Variant, Result: result, Seconds: gr.Seconds, Moves: gr.Moves, Percent: gr.Percent}
+	saveDailyResults(results)
+	if result == "won" {
+		current, _ := dailyStreaks(results, date)
+		TheUI.Toast(fmt.Sprintf("Daily challenge won; your streak is %s", util.Pluralize("day", current)))
+	} else {
+		TheUI.Toast(fmt.Sprintf("Daily challenge lost, %d%% complete", gr.Percent))
+	}
+}
+
+// dailyStreaks returns the number of days in a row, up to today (or yesterday, as there's still time today),
+// that the daily challenge has been won, and the most there have ever been
+func dailyStreaks(results map[string]*DailyResult, today string) (current int, best int) {
+	var dates []string
+	for date, dr := range results {
+		if dr.Result == "won" {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	var run int
+	var prev time.Time
+	for _, date := range dates {
+		t, err := time.Parse(dailyLayout, date)
+		if err != nil {
+			continue
+		}
+		if !prev.IsZero() && t.Sub(prev) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		best = util.Max(best, run)
+		prev = t
+	}
+	if t, err := time.Parse(dailyLayout, today); err == nil && !prev.IsZero() && t.Sub(prev) <= 24*time.Hour {
+		current = run
+	}
+	return current, best
+}
+
+// dailyShareText is a summary of how the daily challenge went, to be pasted somewhere else;
+// it doesn't give away anything about the deal
+func dailyShareText(date string, dr *DailyResult, streak int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "gosol daily challenge %s\n%s: ", date, dr.Variant)
+	if dr.Result == "won" {
+		fmt.Fprintf(&sb, "won in %s and %s", util.FormatDuration(time.Duration(dr.Seconds)*time.Second), util.Pluralize("move", dr.Moves))
+	} else {
+		fmt.Fprintf(&sb, "lost, %d%% complete", dr.Percent)
+	}
+	if streak > 1 {
+		fmt.Fprintf(&sb, "\nStreak: %s", util.Pluralize("day", streak))
+	}
+	return sb.String()
+}
+
+// PlayDaily deals today's daily challenge, or carries on with it if it's already being played
+func (b *Baize) PlayDaily() {
+	date := today()
+	if v := dailyVariant(date); v != ThePreferences.Variant {
+		b.ChangeVariant(v)
+	}
+	if d, ok := dailyDate(b.script.Stock().Seed()); ok && d == date && !b.Complete() {
+		TheUI.Toast("Carry on with today's daily challenge")
+		return
+	}
+	parked := b.parkGame()
+	nextSeed = dailySeed(date)
+	b.NewDeal()
+	if parked && !NoGameSave {
+		b.Save() // so the parked game isn't only in memory
+	}
+	TheUI.Toast(fmt.Sprintf("Daily challenge for %s: %s", date, ThePreferences.Variant))
+}
+
+// parkGame puts the game in progress aside, so dealing the daily challenge doesn't count it as lost.
+// An unfinished challenge from another day isn't parked; it's given up like any other abandoned game.
+// Returns true if there was a game to park
+func (b *Baize) parkGame() bool {
+	if DemoMode || !b.inProgress() {
+		return false
+	}
+	if _, ok := dailyDate(b.script.Stock().Seed()); ok {
+		return false
+	}
+	sg := b.newSavedGame()
+	sg.Parked = true
+	if _, ok := b.savedGames[sg.key()]; ok {
+		return false // there's already one waiting; rather than lose that, this one is given up as usual
+	}
+	if b.savedGames == nil {
+		b.savedGames = make(map[string]*SavedGame)
+	}
+	b.savedGames[sg.key()] = sg
+	b.Reset() // so NewDeal finds nothing to record as lost
+	return true
+}
+
+// unparkGame brings back the game of the current variant that was put aside for the daily challenge, if there is one
+func (b *Baize) unparkGame() bool {
+	key := ThePreferences.Variant + parkedSuffix
+	sg, ok := b.savedGames[key]
+	if !ok {
+		return false
+	}
+	delete(b.savedGames, key)
+	sg.Parked = false
+	if err := b.restoreGame(sg); err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not go back to the game before the daily challenge, because %s", err))
+		return false
+	}
+	if !NoGameSave {
+		b.Save()
+	}
+	TheUI.Toast("Back to the game you were playing before the daily challenge")
+	return true
+}
+
+// ShareDaily copies a summary of today's daily challenge to the clipboard
+func ShareDaily() {
+	date := today()
+	results := loadDailyResults()
+	dr, ok := results[date]
+	if !ok {
+		TheUI.Toast("Finish today's daily challenge first")
+		sound.Play("Blip")
+		return
+	}
+	current, _ := dailyStreaks(results, date)
+	text := dailyShareText(date, dr, current)
+	if err := ui.CopyToClipboard(text); err != nil {
+		log.Println("cannot copy to clipboard", err)
+		// the next best thing
+		for _, line := range strings.Split(text, "\n") {
+			TheUI.Toast(line)
+		}
+		return
+	}
+	TheUI.Toast("Copied to the clipboard")
+}
+
+// ShowDailyDrawer shows today's challenge, the streaks, and calendars of this month and the last two
+func ShowDailyDrawer() {
+	date := today()
+	results := loadDailyResults()
+	current, best := dailyStreaks(results, date)
+
+	entries := []ui.ListEntry{{Icon: "star", Text: "Play today's challenge", Detail: dailyVariant(date), RequestType: "Daily play"}}
+	if dr, ok := results[date]; ok {
+		entries = append(entries, ui.ListEntry{Icon: "done", Text: "Copy today's result", Detail: fmt.Sprintf("%s, %d%%", dr.Result, dr.Percent), RequestType: "Daily share"})
+	}
+	entries = append(entries, ui.ListEntry{Text: fmt.Sprintf("Streak %d", current), Detail: fmt.Sprintf("best %d, played %s", best, util.Pluralize("day", len(results)))})
+
+	now := time.Now()
+	var months []ui.DailyMonth
+	for i := 0; i < 3; i++ {
+		first := time.Date(now.Year(), now.Month()-time.Month(i), 1, 0, 0, 0, 0, time.Local)
+		m := ui.DailyMonth{Month: first, Days: make(map[int]string)}
+		if i == 0 {
+			m.Today = now.Day()
+		}
+		for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+			if dr, ok := results[d.Format(dailyLayout)]; ok {
+				m.Days[d.Day()] = dr.Result
+			}
+		}
+		months = append(months, m)
+	}
+	TheUI.ShowDailyDrawer(entries, months)
+}
@@ -0,0 +1,84 @@
+package sol
+
+import (
+	"testing"
+)
+
+func TestDailySeed(t *testing.T) {
+	for _, date := range []string{"2022-01-01", "2023-02-28", "2024-02-29", "2099-12-31"} {
+		seed := dailySeed(date)
+		if d, ok := dailyDate(seed); !ok || d != date {
+			t.Errorf("%s came back as %s, %v", date, d, ok)
+		}
+	}
+	// a shuffle seeded from the time only uses the bottom 32 bits
+	if _, ok := dailyDate(0xFFFFFFFF); ok {
+		t.Error("an ordinary seed should not be a daily challenge")
+	}
+	if dailySeed("2023-05-06") == dailySeed("2023-06-05") {
+		t.Error("different days should have different seeds")
+	}
+}
+
+func TestDailyVariant(t *testing.T) {
+	n := len(dailyVariants)
+	if v := dailyVariant("2022-01-01"); v != dailyVariants[0] {
+		t.Errorf("first daily challenge should be %s, not %s", dailyVariants[0], v)
+	}
+	if v := dailyVariant(dailyEpoch.AddDate(0, 0, n+1).Format(dailyLayout)); v != dailyVariants[1] {
+		t.Errorf("daily variants should go round, got %s", v)
+	}
+	if v := dailyVariant("2021-12-31"); v != dailyVariants[n-1] {
+		t.Errorf("day before the first daily challenge should be %s, not %s", dailyVariants[n-1], v)
+	}
+	for _, v := range dailyVariants {
+		if _, ok := Variants[v]; !ok {
+			t.Errorf("daily variant %s does not exist", v)
+		}
+	}
+}
+
+func TestDailyStreaks(t *testing.T) {
+	won := &DailyResult{Result: "won"}
+	lost := &DailyResult{Result: "lost"}
+	for _, tc := range []struct {
+		name          string
+		results       map[string]*DailyResult
+		today         string
+		current, best int
+	}{
+		{"nothing played", map[string]*DailyResult{}, "2023-03-10", 0, 0},
+		{"won today", map[string]*DailyResult{"2023-03-10": won}, "2023-03-10", 1, 1},
+		{"not played yet today", map[string]*DailyResult{"2023-03-08": won, "2023-03-09": won}, "2023-03-10", 2, 2},
+		{"missed yesterday", map[string]*DailyResult{"2023-03-07": won, "2023-03-08": won}, "2023-03-10", 0, 2},
+		{"lost in between", map[string]*DailyResult{
+			"2023-03-05": won, "2023-03-06": won, "2023-03-07": won, "2023-03-08": lost, "2023-03-09": won, "2023-03-10": won,
+		}, "2023-03-10", 2, 3},
+		{"over the end of a month", map[string]*DailyResult{"2023-02-27": won, "2023-02-28": won, "2023-03-01": won}, "2023-03-01", 3, 3},
+		{"lost today", map[string]*DailyResult{"2023-03-09": won, "2023-03-10": lost}, "2023-03-10", 1, 1},
+	} {
+		current, best := dailyStreaks(tc.results, tc.today)
+		if current != tc.current || best != tc.best {
+			t.Errorf("%s: got streaks %d and %d, wanted %d and %d", tc.name, current, best, tc.current, tc.best)
+		}
+	}
+}
+
+func TestReplayJournalLeavesParkedGameAlone(t *testing.T) {
+	state := func(bookmark int) *SavableBaize { return &SavableBaize{Bookmark: bookmark} }
+	parked := &SavedGame{Version: savedGameVersion, Variant: "Klondike", Parked: true, UndoStack: []*SavableBaize{state(1), state(2), state(3)}}
+
+	journal := []byte(`{"Seq": 2, "Variant": "Klondike", "Len": 0, "States": [{"Bookmark": 7}, {"Bookmark": 8}]}` + "\n")
+	games, _ := replayJournal([]*SavedGame{parked}, 1, journal)
+	if len(games) != 2 {
+		t.Fatalf("wanted the parked game and the daily challenge, got %d games", len(games))
+	}
+	for _, sg := range games {
+		if sg.Parked && len(sg.UndoStack) != 3 {
+			t.Errorf("parked game was changed by the journal, %d states", len(sg.UndoStack))
+		}
+		if !sg.Parked && len(sg.UndoStack) != 2 {
+			t.Errorf("game in progress replayed incorrectly, %d states", len(sg.UndoStack))
+		}
+	}
+}
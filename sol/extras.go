@@ -27,6 +27,28 @@ func AnyCardsProne(cards []*Card) bool {
 	return false
 }
 
+// CountJokers returns the number of jokers in a slice of cards
+func CountJokers(cards []*Card) int {
+	var n int
+	for _, c := range cards {
+		if c.Joker() {
+			n++
+		}
+	}
+	return n
+}
+
+// LibraryJokers returns the number of jokers in the card library
+func LibraryJokers() int {
+	var n int
+	for i := 0; i < len(CardLibrary); i++ {
+		if CardLibrary[i].Joker() {
+			n++
+		}
+	}
+	return n
+}
+
 func FlipUpExposedCard(p Pile) {
 	if _, isStock := (p).(*Stock); !isStock {
 		if c := p.Peek(); c != nil {
@@ -47,12 +69,18 @@ func MoveCard(src Pile, dst Pile) *Card {
 	return nil
 }
 
+// MoveNamedCard finds a card in src and moves it to dst;
+// if suit is NOSUIT, then the first card found with that ordinal is moved
 func MoveNamedCard(src Pile, suit, ordinal int, dst Pile) {
 
 	// 1. find the card in the src Pile
 	var ID CardID = NewCardID(0, suit, ordinal)
 	var card *Card
 	for _, c := range src.Cards() {
+		if suit == NOSUIT && c.Ordinal() == ordinal && !c.Joker() {
+			card = c
+			break
+		}
 		if SameCard(ID, c.ID) {
 			card = c
 			break
@@ -3,6 +3,7 @@ package sol
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"oddstream.games/gosol/sound"
@@ -11,12 +12,13 @@ import (
 
 // Game represents a game state
 type Game struct {
+	ticks int
 }
 
 var (
 	// DebugMode is a boolean set by command line flag -debug
 	DebugMode bool = false
-	// NoGameLoad is a boolean set by command line flag -noload
+	// NoGameLoad is a boolean set by command line flag -noload; the saved games are not saved over either
 	NoGameLoad bool = false
 	// NoGameSave is a boolean set by command line flag -nosave
 	NoGameSave bool = false
@@ -28,6 +30,12 @@ var (
 	NoCardLerp = false
 	// NoCardFlip stops the cards from animating their flip
 	NoCardFlip = false
+	// ImportFile is a shared custom variant to add and play, set by command line flag -import
+	ImportFile string = ""
+	// ImportStatsFile is statistics exported from another device, to be merged, set by command line flag -importstats
+	ImportStatsFile string = ""
+	// DemoMode plays the DemoVariants by themselves, without loading or saving anything
+	DemoMode = false
 	// CardWidth of cards, start with a silly value to force a rescale/refan
 	CardWidth int = 9
 	// CardHeight of cards, start with a silly value to force a rescale/refan
@@ -70,14 +78,32 @@ var CardLibrary []Card
 
 // NewGame generates a new Game object.
 func NewGame() (*Game, error) {
+	openStorage()
 	ThePreferences.Load()
+	if DemoMode {
+		NoGameLoad = true
+		NoGameSave = true
+		ThePreferences.Variant = DemoVariants[0]
+	}
 	if ThePreferences.Mute {
 		sound.SetVolume(0.0)
 	} else {
 		sound.SetVolume(ThePreferences.Volume)
 	}
+	LoadCustomVariants()
+	if ImportFile != "" && !DemoMode {
+		if name := importCustomVariant(ImportFile); name != "" {
+			ThePreferences.Variant = name
+		}
+	}
 	TheUI = ui.New(Execute)
+	if currentProfile != defaultProfile {
+		TheUI.Toast(fmt.Sprintf("Playing as %s", currentProfile))
+	}
 	TheStatistics = NewStatistics()
+	if ImportStatsFile != "" && !DemoMode {
+		importStatisticsFile(ImportStatsFile)
+	}
 	TheBaize = NewBaize()
 	TheBaize.StartFreshGame()
 	return &Game{}, nil
@@ -89,9 +115,24 @@ func (*Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight
 	return outsideWidth, outsideHeight
 }
 
+// autosaveTicks is how often a changed game is saved while playing, about every ten seconds,
+// so that not much is lost if the app is killed (Android never gets the chance to save on exit)
+const autosaveTicks = 600
+
 // Update updates the current game state.
-func (*Game) Update() error {
+func (g *Game) Update() error {
 	TheBaize.Update()
+	TheBaize.tickTimer()
+	select {
+	case bytes := <-importedStatistics:
+		TheStatistics.mergeStatistics(bytes)
+	default:
+	}
+	g.ticks++
+	// save straight away when the window loses focus, because it might be about to be killed
+	if g.ticks%autosaveTicks == 0 || !ebiten.IsFocused() {
+		TheBaize.Autosave()
+	}
 	if ExitRequested {
 		if !NoGameSave {
 			TheBaize.Save()
@@ -0,0 +1,127 @@
+package sol
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// Every finished game, won or lost, is added to the end of the history, one line of JSON per game,
+// so nothing that's already been written has to be written again
+
+// GameRecord describes one finished game
+type GameRecord struct {
+	Variant string
+	Seed    int64 `json:",omitempty"`
+	Started time.Time
+	Ended   time.Time
+	Seconds int `json:",omitempty"` // time spent playing, which doesn't include time in the background
+	Moves   int `json:",omitempty"`
+	Undos   int `json:",omitempty"`
+	Hints   int `json:",omitempty"` // there aren't any hints yet
+	Percent int
+	Result  string // "won" or "lost"
+	Daily   string `json:",omitempty"` // date of the daily challenge, if it was one
+}
+
+// gameRecord describes the current game, which has just finished
+func (b *Baize) gameRecord(result string) *GameRecord {
+	gr := &GameRecord{
+		Variant: b.LongVariantName(),
+		Seed:    b.script.Stock().Seed(),
+		Started: b.started,
+		Ended:   time.Now(),
+		Seconds: int(b.elapsed / time.Second),
+		Moves:   b.moves,
+		Undos:   b.undos,
+		Percent: b.PercentComplete(),
+		Result:  result,
+	}
+	gr.Daily, _ = dailyDate(gr.Seed)
+	if result == "won" {
+		gr.Percent = 100 // whatever is left on the tableaux, it's done
+	}
+	if gr.Started.IsZero() {
+		gr.Started = gr.Ended.Add(-b.elapsed) // a game saved before there was a history
+	}
+	return gr
+}
+
+// appendGameRecord adds a finished game to the history
+func appendGameRecord(gr *GameRecord) {
+	bytes, err := json.Marshal(gr)
+	if err != nil {
+		log.Println("appendGameRecord Marshal", err)
+		return
+	}
+	if err := TheStorage.Append("history.jsonl", append(bytes, '\n')); err != nil {
+		log.Println("cannot write history", err)
+	}
+}
+
+// loadHistory returns every game in the history, oldest first.
+// A damaged line (probably half written when the app was killed) is skipped
+func loadHistory() []*GameRecord {
+	data, err := TheStorage.Load("history.jsonl")
+	if err != nil {
+		log.Println("cannot load history", err)
+	}
+	var history []*GameRecord
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var gr GameRecord
+		if err := json.Unmarshal(line, &gr); err != nil {
+			log.Println("skipping damaged history", err)
+			continue
+		}
+		history = append(history, &gr)
+	}
+	return history
+}
+
+var historyColumns = []string{"Variant", "Seed", "Started", "Ended", "Seconds", "Moves", "Undos", "Hints", "Percent", "Result", "Daily"}
+
+// historyCSV turns the history into a spreadsheet, with a heading row
+func historyCSV(history []*GameRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(historyColumns)
+	for _, gr := range history {
+		w.Write([]string{
+			gr.Variant,
+			strconv.FormatInt(gr.Seed, 10),
+			gr.Started.Format(time.RFC3339),
+			gr.Ended.Format(time.RFC3339),
+			strconv.Itoa(gr.Seconds),
+			strconv.Itoa(gr.Moves),
+			strconv.Itoa(gr.Undos),
+			strconv.Itoa(gr.Hints),
+			strconv.Itoa(gr.Percent),
+			gr.Result,
+			gr.Daily,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// exportHistory returns the history as CSV or JSON
+func exportHistory(format string) ([]byte, error) {
+	history := loadHistory()
+	switch format {
+	case "csv":
+		return historyCSV(history)
+	case "json":
+		if history == nil {
+			history = []*GameRecord{}
+		}
+		return json.MarshalIndent(history, "", "\t")
+	}
+	return nil, fmt.Errorf("don't know how to export history as %q, only csv or json", format)
+}
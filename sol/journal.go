@@ -0,0 +1,120 @@
+package sol
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// The journal is an append-only record of every change to the undo stacks, written as it happens,
+// so that if the app is killed without saving (Android never gets the chance) at most the last move is lost.
+// Saving the games writes a snapshot of them all, tagged with the sequence number of the last
+// journal entry, and empties the journal. Loading replays whatever is in the journal on top of the snapshot.
+// Nothing is journalled, and the journal is never emptied, unless the saved games were loaded (see -noload)
+
+// journalEntry records one change to a variant's undo stack: cut it to Len states, then append States.
+// Every user move is a single state pushed onto the stack, so most entries are small
+type journalEntry struct {
+	Seq     int64
+	Variant string
+	Seed    int64  `json:",omitempty"`
+	Layout  string `json:",omitempty"`
+	Len     int
+	States  []*SavableBaize `json:",omitempty"`
+	Moves   int             `json:",omitempty"`
+	Elapsed time.Duration   `json:",omitempty"`
+	Undos   int             `json:",omitempty"`
+	Started time.Time
+	Scoring string `json:",omitempty"`
+}
+
+// journal appends a change to the current variant's undo stack to the journal
+func (b *Baize) journal(length int, states []*SavableBaize) {
+	if !b.journalling || NoGameSave || DemoMode {
+		return
+	}
+	b.journalSeq++
+	je := &journalEntry{
+		Seq:     b.journalSeq,
+		Variant: ThePreferences.Variant,
+		Seed:    b.script.Stock().Seed(),
+		Layout:  b.layoutSignature(),
+		Len:     length,
+		States:  states,
+		Moves:   b.moves,
+		Elapsed: b.elapsed,
+		Undos:   b.undos,
+		Started: b.started,
+		Scoring: b.scoring,
+	}
+	bytes, err := json.Marshal(je)
+	if err != nil {
+		log.Println("journal Marshal", err)
+		return
+	}
+	appendToJournal(append(bytes, '\n'))
+}
+
+// startJournal begins journalling changes, after the saved games have been loaded and
+// written back as a fresh snapshot, so the journal starts off empty
+func (b *Baize) startJournal(seq int64) {
+	b.journalSeq = seq
+	b.journalling = true
+	if !NoGameSave && !DemoMode {
+		b.Save()
+		b.unsaved = false
+	}
+}
+
+// replayJournal applies the journal entries made after the snapshot (with sequence number seq)
+// to the saved games, and returns them along with the last sequence number used.
+// A damaged entry (probably half written when the app was killed) ends the replay
+func replayJournal(games []*SavedGame, seq int64, journal []byte) ([]*SavedGame, int64) {
+	byVariant := make(map[string]*SavedGame, len(games))
+	for _, sg := range games {
+		byVariant[sg.key()] = sg // the journal only follows the game in progress, never a parked one
+	}
+	for _, line := range bytes.Split(journal, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var je journalEntry
+		if err := json.Unmarshal(line, &je); err != nil {
+			log.Println("journal ends with a damaged entry", err)
+			break
+		}
+		if je.Seq <= seq {
+			continue // already in the snapshot
+		}
+		seq = je.Seq
+		sg, ok := byVariant[je.Variant]
+		if !ok {
+			sg = &SavedGame{Version: savedGameVersion, Variant: je.Variant}
+			byVariant[je.Variant] = sg
+		}
+		if je.Len > len(sg.UndoStack) {
+			log.Println("journal entry", je.Seq, "does not follow on from the one before")
+			break
+		}
+		if je.Len == 0 {
+			sg.Branches = nil // a whole new undo stack, which the branches may not fit
+		} else {
+			rebaseBranches(sg.Branches, sg.UndoStack, je.Len)
+		}
+		sg.Seed = je.Seed
+		sg.Layout = je.Layout
+		sg.Moves, sg.Elapsed, sg.Undos, sg.Started = je.Moves, je.Elapsed, je.Undos, je.Started
+		sg.Scoring = je.Scoring
+		sg.UndoStack = append(sg.UndoStack[:je.Len], je.States...)
+	}
+
+	games = games[:0]
+	for _, sg := range byVariant {
+		// a virgin game has one state on the undo stack, and isn't worth keeping
+		if len(sg.UndoStack) > 1 {
+			games = append(games, sg)
+		}
+	}
+	return games, seq
+}
@@ -1,212 +1,148 @@
-//go:build linux || windows || android
-
 package sol
 
 import (
 	"encoding/json"
 	"log"
-	"os"
-	"path"
-	"runtime"
 	"time"
 
 	"oddstream.games/gosol/util"
 )
 
-func fullPath(jsonFname string) (string, error) {
-	// os.Getenv("HOME") == "" on WASM
-	// could use something like errors.New("math: square root of negative number")
-	userConfigDir, err := os.UserConfigDir()
-	if err != nil {
-		println(err)
-		return "", err
-	}
-	// println("UserConfigDir", userConfigDir) // /home/gilbert/.config
-	return path.Join(userConfigDir, "oddstream.games", "gosol", jsonFname), nil
-}
-
-func makeConfigDir() {
-	userConfigDir, err := os.UserConfigDir()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	dir := path.Join(userConfigDir, "oddstream.games", "gosol")
-	err = os.MkdirAll(dir, 0755) // https://stackoverflow.com/questions/14249467/os-mkdir-and-os-mkdirall-permission-value
-	if err != nil {
-		log.Fatal(err)
-	}
-	// if path is already a directory, MkdirAll does nothing and returns nil
-}
-
-func loadBytesFromFile(jsonFname string, leaveNoTrace bool) ([]byte, int, error) {
-
-	if runtime.GOARCH == "wasm" {
-		log.Fatal("WASM detected")
-	}
-
-	path, err := fullPath(jsonFname)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	file, err := os.Open(path)
-	if err == nil && file != nil {
-		var bytes []byte
-		var count int
-		fi, err := file.Stat()
-		if err != nil {
-			log.Fatal(err, " getting FileInfo ", path)
-		}
-		if fi.Size() == 0 {
-			log.Print("empty file ", path)
-		} else {
-			bytes = make([]byte, fi.Size()+8)
-			count, err = file.Read(bytes)
-			if err != nil {
-				log.Fatal(err, " reading ", path)
-			}
-		}
-		err = file.Close()
-		if err != nil {
-			log.Fatal(err, " closing ", path)
-		}
-		println("loaded", path)
-		if leaveNoTrace {
-			os.Remove(path)
-		}
-		return bytes, count, nil
-	}
-	// log.Print(err, path)
-	return nil, 0, nil // file does not exist (which is ok)
-}
-
-func saveBytesToFile(bytes []byte, jsonFname string) {
-
-	if runtime.GOARCH == "wasm" {
-		log.Fatal("WASM detected")
-	}
-
-	path, err := fullPath(jsonFname)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	makeConfigDir()
-
-	file, err := os.Create(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-	_, err = file.Write(bytes)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = file.Close()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	println("saved", path)
-}
+// Everything is loaded from and saved to TheStorage, which is a directory of files on the desktop,
+// localStorage in a browser, or memory in tests
 
-// Load an already existing Preferences object from file
+// Load an already existing Preferences object
 func (prefs *Preferences) Load() {
 	if DebugMode {
 		defer util.Duration(time.Now(), "Preferences.Load")
 	}
-	bytes, count, err := loadBytesFromFile("preferences.json", false)
-	if err != nil || count == 0 || bytes == nil {
-		return
-	}
-
-	// golang gotcha reslice buffer to number of bytes actually read
-	err = json.Unmarshal(bytes[:count], prefs)
-	if err != nil {
-		log.Panic("Preferences.Load Unmarshal", err)
-	}
+	loadData("preferences.json", func(bytes []byte) error { return json.Unmarshal(bytes, prefs) })
 }
 
-// Save writes the Preferences object to file
+// Save writes the Preferences object
 func (prefs *Preferences) Save() {
+	if DemoMode {
+		return // the demo changes the variant, which shouldn't stick
+	}
 	if DebugMode {
 		defer util.Duration(time.Now(), "Preferences.Save")
 	}
 	// warning - calling ebiten function ouside RunGame loop will cause fatal panic
 	bytes, err := json.MarshalIndent(prefs, "", "\t")
 	if err != nil {
-		log.Fatal(err)
+		log.Println("Preferences.Save Marshal", err)
+		return
 	}
-	saveBytesToFile(bytes, "preferences.json")
+	saveData("preferences.json", bytes)
 }
 
-// Load statistics for all variants from JSON to an already-created Statistics object
-func (s *Statistics) Load() {
+// Load statistics for all variants from JSON to an already-created Statistics object,
+// returning true if they had to be migrated or repaired, and so need saving
+func (s *Statistics) Load() bool {
 	if DebugMode {
 		defer util.Duration(time.Now(), "Statistics.Load")
 	}
-	bytes, count, err := loadBytesFromFile("statistics.json", false)
-	if err != nil || count == 0 || bytes == nil {
-		return
-	}
-
-	// golang gotcha reslice buffer to number of bytes actually read
-	err = json.Unmarshal(bytes[:count], s)
-	if err != nil {
-		log.Fatal(err)
-	}
+	var loaded bool
+	loadData("statistics.json", func(bytes []byte) error {
+		// into a fresh one, so a file that fails halfway doesn't leave anything behind for the backup to land on
+		fresh := Statistics{StatsMap: make(map[string]*VariantStatistics)}
+		if err := json.Unmarshal(bytes, &fresh); err != nil {
+			return err
+		}
+		*s = fresh
+		loaded = true
+		return nil
+	})
+	if !loaded {
+		return false
+	}
+	s.verify()
+	version := s.Version
+	s.migrate()
+	renamed := s.renameVariants()
+	return s.repair() || renamed || version != s.Version
 }
 
-// Save writes the Statistics object to file
+// Save writes the Statistics object
 func (s *Statistics) Save() {
 	if DebugMode {
 		defer util.Duration(time.Now(), "Statistics.Save")
 	}
+	s.Version = statisticsVersion
+	s.Checksum = s.checksum()
 	bytes, err := json.MarshalIndent(s, "", "\t")
 	if err != nil {
-		log.Fatal(err)
+		log.Println("Statistics.Save Marshal", err)
+		return
 	}
-
-	saveBytesToFile(bytes, "statistics.json")
+	saveData("statistics.json", bytes)
 }
 
-// Save the entire undo stack to file
+// Save the games in progress in every variant, which makes the journal redundant.
+// If the saved games weren't loaded, there's nothing to save them from, and saving would write over them
 func (b *Baize) Save() {
+	if NoGameLoad {
+		return
+	}
 	if DebugMode {
 		defer util.Duration(time.Now(), "Baize.Save")
 	}
-	// do not bother to save virgin or completed games
-	// if len(b.undoStack) < 2 || b.Complete() {
-	// 	return
-	// }
-
-	bytes, err := json.MarshalIndent(b.undoStack, "", "\t")
+	// not indented, because undo stacks get big, and localStorage is small
+	bytes, err := json.Marshal(b.snapshot())
 	if err != nil {
-		log.Fatal(err)
+		log.Println("Baize.Save Marshal", err)
+		return
+	}
+	// the journal is only redundant if it was replayed into the games being saved
+	if saveData("saved.json", bytes) == nil && b.journalling {
+		truncateJournal()
 	}
-
-	saveBytesToFile(bytes, "saved.json")
 }
 
-func LoadUndoStack() []*SavableBaize {
-	if DebugMode {
-		defer util.Duration(time.Now(), "LoadUndoStack")
+// appendToJournal adds an entry to the end of the journal
+func appendToJournal(bytes []byte) {
+	if err := TheStorage.Append("journal.jsonl", bytes); err != nil {
+		log.Println("cannot write journal", err)
 	}
-	bytes, count, err := loadBytesFromFile("saved.json", true)
-	if err != nil || count == 0 || bytes == nil {
-		return nil
+}
+
+func truncateJournal() {
+	if err := TheStorage.Remove("journal.jsonl"); err != nil {
+		log.Println("cannot truncate journal", err)
 	}
+}
 
-	var undoStack []*SavableBaize
-	// golang gotcha reslice buffer to number of bytes actually read
-	err = json.Unmarshal(bytes[:count], &undoStack)
+// LoadSavedGames loads the games in progress in every variant, and replays the journal on top of them.
+// Returns the games, and the sequence number of the last journal entry
+func LoadSavedGames() ([]*SavedGame, int64) {
+	if DebugMode {
+		defer util.Duration(time.Now(), "LoadSavedGames")
+	}
+	var games []*SavedGame
+	var seq int64
+	loadData("saved.json", func(bytes []byte) (err error) {
+		games, seq, err = unmarshalSavedGames(bytes)
+		return
+	})
+	journal, err := TheStorage.Load("journal.jsonl")
 	if err != nil {
-		log.Fatal(err)
+		log.Println("cannot load journal", err)
 	}
+	return replayJournal(games, seq, journal)
+}
 
-	if len(undoStack) > 0 {
-		return undoStack
+func loadCustomVariants() []*CustomVariant {
+	var customs []*CustomVariant
+	// custom variants belong to everyone, whichever profile they were made in
+	loadDataFrom(theSharedStorage(), "customs.json", func(bytes []byte) error { return json.Unmarshal(bytes, &customs) })
+	return customs
+}
+
+func saveCustomVariants() {
+	bytes, err := json.MarshalIndent(CustomVariants, "", "\t")
+	if err != nil {
+		log.Println("saveCustomVariants Marshal", err)
+		return
 	}
-	return nil
+	saveDataTo(theSharedStorage(), "customs.json", bytes)
 }
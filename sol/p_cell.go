@@ -5,6 +5,7 @@ package sol
 
 import (
 	"errors"
+	"fmt"
 	"image"
 )
 
@@ -22,15 +23,23 @@ func (self *Cell) CanAcceptCard(card *Card) (bool, error) {
 	if card.Prone() {
 		return false, errors.New("Cannot add a face down card")
 	}
-	if !self.Empty() {
+	return self.roomFor([]*Card{card})
+}
+
+// roomFor checks the Cell isn't full; it holds one card, unless the script has given it a capacity
+func (self *Cell) roomFor(tail []*Card) (bool, error) {
+	if self.capacity == 0 && !self.Empty() {
 		return false, errors.New("A Cell can only contain one card")
 	}
+	if self.overCapacity(tail) {
+		return false, fmt.Errorf("This Cell can only contain %d cards", self.capacity)
+	}
 	return true, nil
 }
 
 func (self *Cell) CanAcceptTail(tail []*Card) (bool, error) {
-	if !self.Empty() {
-		return false, errors.New("A Cell can only contain one card")
+	if ok, err := self.roomFor(tail); !ok {
+		return false, err
 	}
 	if len(tail) > 1 {
 		return false, errors.New("Cannot move more than one card to a Cell")
@@ -50,6 +59,9 @@ func (*Cell) Conformant() bool {
 }
 
 func (self *Cell) Complete() bool {
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
 	return self.Empty()
 }
 
@@ -29,7 +29,7 @@ func (self *Discard) CanAcceptTail(tail []*Card) (bool, error) {
 	if AnyCardsProne(tail) {
 		return false, errors.New("Cannot move a face down card to a Discard")
 	}
-	if len(tail) != len(CardLibrary)/len(TheBaize.script.Discards()) {
+	if len(tail)-CountJokers(tail) != self.Capacity() {
 		return false, errors.New("Can only move a full set of cards to a Discard")
 	}
 	return TheBaize.script.TailMoveError(tail) // check cards are conformant
@@ -52,15 +52,27 @@ func (*Discard) Conformant() bool {
 }
 
 func (self *Discard) Complete() bool {
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
 	if self.Empty() {
 		return true
 	}
-	if self.Len() == len(CardLibrary)/len(TheBaize.script.Discards()) {
+	if self.Len()-CountJokers(self.cards) == self.Capacity() {
 		return true
 	}
 	return false
 }
 
+// Capacity returns the number of cards in a full set, not counting any jokers in the set;
+// unless the script has said otherwise, the cards are shared equally between the Discards
+func (self *Discard) Capacity() int {
+	if self.capacity > 0 {
+		return self.capacity
+	}
+	return (len(CardLibrary) - LibraryJokers()) / len(TheBaize.script.Discards())
+}
+
 func (*Discard) UnsortedPairs() int {
 	// you can only put a sorted sequence into a Discard, so this will always be zero
 	return 0
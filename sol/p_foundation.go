@@ -22,7 +22,17 @@ func (self *Foundation) CanAcceptCard(card *Card) (bool, error) {
 	if card.Prone() {
 		return false, errors.New("Cannot add a face down card")
 	}
-	if self.Len() == len(CardLibrary)/len(TheBaize.script.Foundations()) {
+	if card.Wild() {
+		// a joker can go on any started Foundation, even a full one
+		if self.Empty() {
+			return false, errors.New("A joker cannot start a Foundation")
+		}
+		return true, nil
+	}
+	if top := self.Peek(); top != nil && top.Joker() && SameCard(top.ID, card.ID) {
+		return true, nil // the real card can cover the joker that stood in for it
+	}
+	if self.Len()-CountJokers(self.cards) >= self.Capacity() {
 		return false, errors.New("The Foundation is full")
 	}
 	var tail []*Card = []*Card{card}
@@ -34,7 +44,29 @@ func (self *Foundation) CanAcceptTail(tail []*Card) (bool, error) {
 	if len(tail) > 1 {
 		return false, errors.New("Cannot move more than one card to a Foundation")
 	}
-	return TheBaize.script.TailAppendError(self, tail)
+	return self.CanAcceptCard(tail[0])
+}
+
+// Push a card onto the Foundation; a joker takes on the identity of the card it is standing in for
+func (self *Foundation) Push(c *Card) {
+	if c.Wild() && !self.Empty() {
+		self.pretend(c)
+	}
+	self.Core.Push(c)
+}
+
+// pretend finds the card that would next go on this Foundation, and makes the joker into it.
+// If there isn't one (eg the Foundation is full) the joker stays wild
+func (self *Foundation) pretend(joker *Card) {
+	for suit := CLUB; suit <= SPADE; suit++ {
+		for ord := 1; ord < 14; ord++ {
+			var tail []*Card = []*Card{{ID: NewCardID(0, suit, ord)}}
+			if ok, _ := TheBaize.script.TailAppendError(self, tail); ok {
+				joker.Pretend(suit, ord)
+				return
+			}
+		}
+	}
 }
 
 func (*Foundation) TailTapped([]*Card) {
@@ -50,7 +82,19 @@ func (*Foundation) Conformant() bool {
 }
 
 func (self *Foundation) Complete() bool {
-	return self.Len() == len(CardLibrary)/len(TheBaize.script.Foundations())
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
+	return self.Len()-CountJokers(self.cards) == self.Capacity()
+}
+
+// Capacity returns the number of cards, not counting jokers, that make a full Foundation;
+// unless the script has said otherwise, the cards are shared equally between the Foundations
+func (self *Foundation) Capacity() int {
+	if self.capacity > 0 {
+		return self.capacity
+	}
+	return (len(CardLibrary) - LibraryJokers()) / len(TheBaize.script.Foundations())
 }
 
 func (*Foundation) UnsortedPairs() int {
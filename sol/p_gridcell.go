@@ -0,0 +1,97 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"errors"
+	"image"
+
+	"oddstream.games/gosol/sound"
+)
+
+// GridCell is one position in a fixed grid of cards (eg Montana).
+// It holds at most one card, and whether it will accept a card
+// is decided by the script, usually by looking at the card in the neighbouring GridCell
+type GridCell struct {
+	Core
+	neighbour *GridCell // the GridCell to the left of this one, or nil if this is the first in a row
+}
+
+func NewGridCell(slot image.Point, neighbour *GridCell) *GridCell {
+	gc := &GridCell{Core: NewCore("GridCell", slot, FAN_NONE, MOVE_ONE), neighbour: neighbour}
+	TheBaize.AddPile(gc)
+	return gc
+}
+
+func (self *GridCell) Neighbour() *GridCell {
+	return self.neighbour
+}
+
+func (self *GridCell) CanAcceptCard(card *Card) (bool, error) {
+	if card.Prone() {
+		return false, errors.New("Cannot add a face down card")
+	}
+	if !self.Empty() {
+		return false, errors.New("A GridCell can only contain one card")
+	}
+	var tail []*Card = []*Card{card}
+	return TheBaize.script.TailAppendError(self, tail)
+}
+
+func (self *GridCell) CanAcceptTail(tail []*Card) (bool, error) {
+	if !self.Empty() {
+		return false, errors.New("A GridCell can only contain one card")
+	}
+	if len(tail) > 1 {
+		return false, errors.New("Cannot move more than one card to a GridCell")
+	}
+	if AnyCardsProne(tail) {
+		return false, errors.New("Cannot move a face down card")
+	}
+	return TheBaize.script.TailAppendError(self, tail)
+}
+
+// TailTapped moves the card into the first gap that will take it
+func (self *GridCell) TailTapped(tail []*Card) {
+	for _, gc := range TheBaize.script.Grid() {
+		if gc == self {
+			continue
+		}
+		if ok, _ := gc.CanAcceptTail(tail); ok {
+			MoveCard(self, gc)
+			return
+		}
+	}
+	sound.Play("Blip")
+}
+
+func (*GridCell) Collect() {
+	// over-ride Core collect to do nothing
+}
+
+func (self *GridCell) Conformant() bool {
+	return self.Complete()
+}
+
+// Complete returns true if this GridCell is empty, or
+// it's card would be accepted here if it were moved in again
+// (the script's TailAppendError doesn't know or care if the GridCell is occupied)
+func (self *GridCell) Complete() bool {
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
+	if self.Empty() {
+		return true
+	}
+	ok, _ := TheBaize.script.TailAppendError(self, self.cards)
+	return ok
+}
+
+func (self *GridCell) UnsortedPairs() int {
+	// the card in a GridCell pairs with the card in it's neighbour
+	if self.Complete() {
+		return 0
+	}
+	return 1
+}
@@ -35,6 +35,9 @@ func (self *Reserve) Conformant() bool {
 }
 
 func (self *Reserve) Complete() bool {
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
 	return self.Empty()
 }
 
@@ -46,6 +46,7 @@ func CreateCardLibrary(packs int, suits int, cardFilter *[14]bool, jokersPerPack
 		}
 		for i := 0; i < jokersPerPack; i++ {
 			var c Card = NewCard(pack, NOSUIT, 0) // NOSUIT and ordinal == 0 creates a joker
+			c.ID |= NewJokerID(pack, i)
 			CardLibrary = append(CardLibrary, c)
 		}
 	}
@@ -54,6 +55,7 @@ func CreateCardLibrary(packs int, suits int, cardFilter *[14]bool, jokersPerPack
 
 type Stock struct {
 	Core
+	seed int64 // of the shuffle that dealt the game, so a deal can be identified; redeals don't change it
 }
 
 func (self *Stock) FillFromLibrary() {
@@ -72,19 +74,51 @@ func (self *Stock) FillFromLibrary() {
 		// so that a new deal makes the spinning cards fall into place
 		// without going back to the CardStartPoint
 		c.SetProne(true)
+		c.ResetJoker()
 	}
 }
 
+// nextSeed, if not zero, is used by the next shuffle instead of a random one
+var nextSeed int64
+
+// Seed returns the seed used to shuffle the cards, or zero if they weren't shuffled
+func (self *Stock) Seed() int64 {
+	return self.seed
+}
+
+// Shuffle shuffles the cards for a new deal, and remembers the seed, which identifies the deal
 func (self *Stock) Shuffle() {
 
 	if !self.Valid() {
 		log.Fatal("invalid stock")
 	}
+	self.seed = 0
 	if NoShuffle {
 		log.Println("not shuffling cards")
 		return
 	}
 	seed := time.Now().UnixNano() & 0xFFFFFFFF
+	if nextSeed != 0 {
+		// a particular deal has been asked for, like the daily challenge
+		seed, nextSeed = nextSeed, 0
+	}
+	self.seed = seed
+	self.shuffle(seed)
+}
+
+// Reshuffle shuffles the cards gathered up for a redeal; the game is still the deal it started as,
+// so the seed isn't changed
+func (self *Stock) Reshuffle() {
+	if !self.Valid() {
+		log.Fatal("invalid stock")
+	}
+	if NoShuffle {
+		return
+	}
+	self.shuffle(time.Now().UnixNano() & 0xFFFFFFFF)
+}
+
+func (self *Stock) shuffle(seed int64) {
 	if DebugMode {
 		log.Println("shuffle with seed", seed)
 	}
@@ -143,6 +177,9 @@ func (self *Stock) Conformant() bool {
 }
 
 func (self *Stock) Complete() bool {
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
 	return self.Empty()
 }
 
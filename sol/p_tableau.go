@@ -26,6 +26,9 @@ func (self *Tableau) CanAcceptCard(card *Card) (bool, error) {
 		return false, errors.New("Cannot add a face down card")
 	}
 	var tail []*Card = []*Card{card}
+	if self.overCapacity(tail) {
+		return false, fmt.Errorf("This Tableau can only contain %d cards", self.capacity)
+	}
 	return TheBaize.script.TailAppendError(self, tail)
 }
 
@@ -59,6 +62,9 @@ func (self *Tableau) CanAcceptTail(tail []*Card) (bool, error) {
 	if AnyCardsProne(tail) {
 		return false, errors.New("Cannot add a face down card")
 	}
+	if self.overCapacity(tail) {
+		return false, fmt.Errorf("This Tableau can only contain %d cards", self.capacity)
+	}
 	// we couldn't check MOVE_PLUS_ONE in pile.CanMoveTail
 	// because we didn't then know the destination pile
 	// which we need to know to calculate power moves
@@ -99,16 +105,22 @@ func (self *Tableau) Complete() bool {
 	           (number of discard piles is packs * suits)
 	           (Simple Simon has 10 tableau piles and 4 discard piles)
 	           (Spider has 10 tableau piles and 8 discard piles)
+	       (c) or whatever the script says, if it has set a complete func
 	*/
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
 	if self.Empty() {
 		return true
 	}
-	if len(TheBaize.script.Discards()) > 0 {
-		if self.Len() == len(CardLibrary)/len(TheBaize.script.Discards()) {
+	// the discards may be different sizes, so the pile is complete if it would fill any of them
+	for _, d := range TheBaize.script.Discards() {
+		if self.Len()-CountJokers(self.cards) == d.Capacity() {
 			// eg 13 == 52 / 4
 			if TheBaize.script.UnsortedPairs(self) == 0 {
 				return true
 			}
+			break
 		}
 	}
 	return false
@@ -42,6 +42,9 @@ func (self *Waste) Conformant() bool {
 }
 
 func (self *Waste) Complete() bool {
+	if self.completeFunc != nil {
+		return self.completeFunc(self)
+	}
 	return self.Empty()
 }
 
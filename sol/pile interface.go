@@ -25,6 +25,7 @@ type Pile interface {
 	Valid() bool
 	Reset()
 	Hidden() bool
+	Category() string
 	IsStock() bool
 	IsTableau() bool
 	Cards() []*Card
@@ -63,6 +64,9 @@ type Pile interface {
 	MakeTail(*Card) []*Card
 	ApplyToCards(func(*Card))
 	BuryCards(int)
+	Capacity() int // Foundation and Discard work out a default
+	SetCapacity(int)
+	SetCompleteFunc(func(Pile) bool)
 
 	Savable() *SavablePile
 	UpdateFromSavable(*SavablePile)
@@ -76,7 +80,7 @@ type Pile interface {
 	CreateBackgroundImage()
 	Draw(*ebiten.Image)
 
-	// implemented by Cell, Discard, Foundation, Reserve, Stock, Tableau, Waste
+	// implemented by Cell, Discard, Foundation, GridCell, Reserve, Stock, Tableau, Waste
 	CanAcceptCard(*Card) (bool, error)
 	CanAcceptTail([]*Card) (bool, error)
 	TailTapped([]*Card)
@@ -25,6 +25,7 @@ type Preferences struct {
 	PreferredWindow                 bool
 	CardRatio                       float64
 	FixedCardWidth, FixedCardHeight int
+	Scoring                         string // name of one of the ScoringModels
 }
 
 // ThePreferences holds serialized game progress data
@@ -50,4 +51,5 @@ var ThePreferences = &Preferences{
 	FixedCardHeight:  122,
 	CardRatio:        1.357,
 	MarkMovableCards: false,
+	Scoring:          "None",
 }
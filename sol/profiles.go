@@ -0,0 +1,216 @@
+package sol
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/ui"
+)
+
+// Each person who plays on a device can have a profile of their own, with their own preferences,
+// statistics, achievements and saved games. The default profile keeps everything where it always was,
+// so nothing moves for someone who never makes another; the others are kept in a subdirectory each,
+// under profiles. The list of profiles, and the custom variants, are shared by everyone
+
+const defaultProfile = "Default"
+
+// Profile is the profile to play as, set by command line flag -profile;
+// if empty, it's whichever was used last
+var Profile string = ""
+
+// currentProfile is the name of the profile being played
+var currentProfile = defaultProfile
+
+// profileName is typed into the profiles drawer, for the next new profile
+var profileName string
+
+// defaultPreferences are what a new profile starts with
+var defaultPreferences = *ThePreferences
+
+// profilesFile is kept in the shared storage
+type profilesFile struct {
+	Current  string
+	Profiles []string // not including the default one
+}
+
+func loadProfiles() *profilesFile {
+	pf := &profilesFile{Current: defaultProfile}
+	loadDataFrom(sharedStorage, "profiles.json", func(bytes []byte) error { return json.Unmarshal(bytes, pf) })
+	return pf
+}
+
+func saveProfiles(pf *profilesFile) {
+	bytes, err := json.MarshalIndent(pf, "", "\t")
+	if err != nil {
+		log.Println("saveProfiles Marshal", err)
+		return
+	}
+	saveDataTo(sharedStorage, "profiles.json", bytes)
+}
+
+func (pf *profilesFile) has(name string) bool {
+	if name == defaultProfile {
+		return true
+	}
+	for _, p := range pf.Profiles {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validProfileName is true if the name will do as the name of a directory on any system
+func validProfileName(name string) bool {
+	if name == "" || len(name) > 32 || strings.TrimSpace(name) != name {
+		return false
+	}
+	for _, r := range name {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func profileStorage(name string) Storage {
+	if name == defaultProfile {
+		return sharedStorage
+	}
+	return sharedStorage.Sub("profiles").Sub(name)
+}
+
+// openStorage sets TheStorage to the storage of the profile given by -profile, or the one used last.
+// A profile named by -profile that doesn't exist yet is made
+func openStorage() {
+	if sharedStorage != nil {
+		return // already open
+	}
+	if TheStorage == nil {
+		TheStorage = defaultStorage()
+	}
+	sharedStorage = TheStorage
+	pf := loadProfiles()
+	name := pf.Current
+	if Profile != "" {
+		if validProfileName(Profile) {
+			name = Profile
+		} else {
+			log.Printf("ignoring profile '%s'; use letters, digits, spaces, - and _", Profile)
+		}
+	}
+	if !pf.has(name) {
+		if name == Profile {
+			pf.Profiles = append(pf.Profiles, name)
+		} else {
+			name = defaultProfile // the list has lost it somehow
+		}
+	}
+	if name != pf.Current {
+		pf.Current = name
+		saveProfiles(pf)
+	}
+	currentProfile = name
+	TheStorage = profileStorage(name)
+}
+
+// SwitchProfile puts away everything belonging to the current profile, and carries on where the other one left off
+func SwitchProfile(name string) {
+	pf := loadProfiles()
+	if !pf.has(name) {
+		TheUI.Toast(fmt.Sprintf("There is no profile called '%s'", name))
+		return
+	}
+	if name == currentProfile {
+		return
+	}
+
+	if !NoGameSave {
+		TheBaize.Save()
+	}
+	ThePreferences.Save()
+	// nothing more should be written to the old profile's journal, and nothing yet to the new one's
+	TheBaize.journalling = false
+
+	pf.Current = name
+	saveProfiles(pf)
+	currentProfile = name
+	TheStorage = profileStorage(name)
+
+	*ThePreferences = defaultPreferences
+	ThePreferences.Load()
+	if _, ok := Variants[ThePreferences.Variant]; !ok {
+		ThePreferences.Variant = defaultPreferences.Variant
+	}
+	if ThePreferences.Mute {
+		sound.SetVolume(0.0)
+	} else {
+		sound.SetVolume(ThePreferences.Volume)
+	}
+	TheStatistics = NewStatistics()
+
+	TheBaize.savedGames = nil
+	TheBaize.StartFreshGame()
+	if !NoGameLoad {
+		TheBaize.SetSavedGames(LoadSavedGames())
+	}
+	TheUI.Toast(fmt.Sprintf("Playing as %s", name))
+}
+
+// newProfileName makes up a name for a new profile, for when one isn't typed
+func newProfileName(pf *profilesFile) string {
+	var name string
+	for n := len(pf.Profiles) + 2; ; n++ {
+		name = fmt.Sprintf("Player %d", n)
+		if !pf.has(name) {
+			return name
+		}
+	}
+}
+
+// NewProfile makes a new profile, called whatever was typed into the profiles drawer
+// (or a made up name, if nothing was), and switches to it; -profile can also be used to make one
+func NewProfile() {
+	pf := loadProfiles()
+	name := strings.TrimSpace(profileName)
+	if name == "" {
+		name = newProfileName(pf)
+	}
+	if !validProfileName(name) {
+		TheUI.Toast("A profile name can only have letters, digits, spaces, - and _")
+		sound.Play("Blip")
+		ShowProfilesDrawer()
+		return
+	}
+	if pf.has(name) {
+		TheUI.Toast(fmt.Sprintf("There is already a profile called '%s'", name))
+		sound.Play("Blip")
+		ShowProfilesDrawer()
+		return
+	}
+	profileName = ""
+	pf.Profiles = append(pf.Profiles, name)
+	saveProfiles(pf)
+	SwitchProfile(name)
+}
+
+// ShowProfilesDrawer lists the profiles, with the current one checked
+func ShowProfilesDrawer() {
+	pf := loadProfiles()
+	var entries []ui.ListEntry
+	for _, name := range append([]string{defaultProfile}, pf.Profiles...) {
+		e := ui.ListEntry{Icon: "radio_button_unchecked", Text: name, RequestType: "Profile", Data: name}
+		if name == currentProfile {
+			e.Icon = "radio_button_checked"
+			e.Detail = "Playing now"
+		}
+		entries = append(entries, e)
+	}
+	entries = append(entries, ui.ListEntry{Text: profileName, Placeholder: newProfileName(pf), RequestType: "Profile name", Submit: "New profile"})
+	entries = append(entries, ui.ListEntry{Icon: "bookmark_add", Text: "New profile", Detail: "With its own settings, statistics and saved games", RequestType: "New profile"})
+	TheUI.ShowListDrawer("Profiles", entries)
+}
@@ -0,0 +1,317 @@
+package sol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Each variant can have a game in progress. The current variant's game lives in Baize.undoStack,
+// the others wait in Baize.savedGames until the user switches back to them.
+
+// savedGameVersion is the current format of a SavedGame.
+//
+//	0 - saved.json was just the undo stack of ThePreferences.Variant
+//	1 - saved.json was a map of variant name to undo stack
+//	2 - saved.json is a list of SavedGame, which describe themselves
+//	3 - each state has a list of named Bookmarks, and the game has Branches
+//	4 - a game can be Parked while the daily challenge is played
+//	5 - each game keeps the Scoring model it was dealt with
+//
+// saved.json itself is now a savedGamesFile, which ties the games to the journal
+const savedGameVersion = 5
+
+// SavedGame wraps an undo stack with enough information to check it
+// still fits the variant it's being loaded into
+type SavedGame struct {
+	Version   int
+	Variant   string
+	Seed      int64  `json:",omitempty"` // of the shuffle that dealt this game
+	Layout    string `json:",omitempty"` // see Baize.layoutSignature; empty if not known
+	UndoStack []*SavableBaize
+	Branches  []*Branch     `json:",omitempty"` // see bookmarks.go
+	Moves     int           `json:",omitempty"` // see timer.go
+	Elapsed   time.Duration `json:",omitempty"`
+	Undos     int           `json:",omitempty"`
+	Started   time.Time     // see history.go
+	Parked    bool          `json:",omitempty"` // put aside while the daily challenge is played, see daily.go
+	Scoring   string        `json:",omitempty"` // see scoring.go
+}
+
+// parkedSuffix tells apart a parked game from the game in progress of the same variant
+const parkedSuffix = " (parked)"
+
+// key is what the game is known by in Baize.savedGames
+func (sg *SavedGame) key() string {
+	if sg.Parked {
+		return sg.Variant + parkedSuffix
+	}
+	return sg.Variant
+}
+
+// savedGamesFile is a snapshot of all the games in progress
+type savedGamesFile struct {
+	Seq   int64 // of the last journal entry included in the snapshot
+	Games []*SavedGame
+}
+
+// savedGameMigrations bring a SavedGame of version n up to version n+1
+var savedGameMigrations = map[int]func(*SavedGame){
+	// version 2 added Seed and Layout, which can't be found out for an old game, so are left empty
+	1: func(*SavedGame) {},
+	// version 3 replaced the single bookmark with a list of named ones
+	2: func(sg *SavedGame) {
+		for _, sb := range sg.UndoStack {
+			if sb.Bookmark > 0 && len(sb.Bookmarks) == 0 {
+				sb.Bookmarks = []Bookmark{{Name: "Bookmark 1", Len: sb.Bookmark}}
+			}
+			sb.Bookmark = 0
+		}
+	},
+	// version 4 added Parked, which older games aren't
+	3: func(*SavedGame) {},
+	// version 5 added Scoring; older games were scored with whatever was chosen at the time
+	4: func(sg *SavedGame) {
+		sg.Scoring = ThePreferences.Scoring
+	},
+}
+
+func (sg *SavedGame) migrate() {
+	for sg.Version < savedGameVersion {
+		if fn, ok := savedGameMigrations[sg.Version]; ok {
+			fn(sg)
+		}
+		sg.Version++
+	}
+}
+
+// layoutSignature summarises the piles and cards of the current variant, so a saved game
+// can be checked against it. Pile positions aren't included, so mirroring the baize doesn't matter
+func (b *Baize) layoutSignature() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d", len(CardLibrary))
+	for _, p := range b.piles {
+		sb.WriteString(",")
+		sb.WriteString(p.Category())
+	}
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(sb.String())))
+}
+
+// newSavedGame wraps the current game
+func (b *Baize) newSavedGame() *SavedGame {
+	return &SavedGame{
+		Version:   savedGameVersion,
+		Variant:   ThePreferences.Variant,
+		Seed:      b.script.Stock().Seed(),
+		Layout:    b.layoutSignature(),
+		UndoStack: b.undoStack,
+		Branches:  b.branches,
+		Moves:     b.moves,
+		Elapsed:   b.elapsed,
+		Undos:     b.undos,
+		Started:   b.started,
+		Scoring:   b.scoring,
+	}
+}
+
+// check returns an error if the saved game can't be loaded into any variant
+func (sg *SavedGame) check() error {
+	if sg.Version > savedGameVersion {
+		return errors.New("it was saved by a newer version of the game")
+	}
+	if len(sg.UndoStack) == 0 {
+		return errors.New("it is empty")
+	}
+	for _, br := range sg.Branches {
+		if br.Fork > len(sg.UndoStack) {
+			return errors.New("it has a branch that doesn't fit")
+		}
+	}
+	return nil
+}
+
+// checkSavedGame returns an error if the saved game can't be loaded into the current variant
+func (b *Baize) checkSavedGame(sg *SavedGame) error {
+	if err := sg.check(); err != nil {
+		return err
+	}
+	if sg.Variant != ThePreferences.Variant {
+		return fmt.Errorf("it is a game of %s", sg.Variant)
+	}
+	if sg.Layout != "" && sg.Layout != b.layoutSignature() {
+		return errors.New("the layout of the variant has changed")
+	}
+	// older saves don't have a layout signature, so check each position the hard way
+	states := sg.UndoStack
+	for _, br := range sg.Branches {
+		states = append(states[:len(states):len(states)], br.States...)
+	}
+	used := make([]bool, len(CardLibrary))
+	for _, sav := range states {
+		if len(sav.Piles) != len(b.piles) {
+			return errors.New("it has a different number of piles")
+		}
+		var cards int
+		for i, sp := range sav.Piles {
+			if sp.Category != b.piles[i].Category() {
+				return fmt.Errorf("it has a %s where there should be a %s", sp.Category, b.piles[i].Category())
+			}
+			cards += len(sp.Cards)
+		}
+		if cards != len(CardLibrary) {
+			return errors.New("it has a different number of cards")
+		}
+		// every card has to be one of this variant's, and only once, or it can't be rebuilt
+		for i := range used {
+			used[i] = false
+		}
+		for _, sp := range sav.Piles {
+			for _, cid := range sp.Cards {
+				i := libraryIndex(cid)
+				if i < 0 {
+					return fmt.Errorf("it has a card (%s) that isn't in this variant", cid)
+				}
+				if used[i] {
+					return fmt.Errorf("it has the same card (%s) twice", cid)
+				}
+				used[i] = true
+			}
+		}
+	}
+	return nil
+}
+
+// inProgress returns true if the current game has been started, but not finished
+func (b *Baize) inProgress() bool {
+	// a virgin game has one state on the undo stack
+	return len(b.undoStack) > 1 && !b.Complete()
+}
+
+// stashGame puts the current game to one side, so it can be resumed later
+func (b *Baize) stashGame() {
+	if DemoMode {
+		return
+	}
+	if !b.inProgress() {
+		b.journal(0, nil) // so replaying the journal doesn't bring back a finished game
+		return
+	}
+	if b.savedGames == nil {
+		b.savedGames = make(map[string]*SavedGame)
+	}
+	b.savedGames[ThePreferences.Variant] = b.newSavedGame()
+}
+
+// resumeGame replaces the freshly dealt game with the one that was stashed for this variant, if any.
+// A saved game that doesn't fit is thrown away, rather than risk a panic halfway through loading it
+func (b *Baize) resumeGame() {
+	sg, ok := b.savedGames[ThePreferences.Variant]
+	if !ok {
+		return
+	}
+	delete(b.savedGames, ThePreferences.Variant)
+	if err := b.restoreGame(sg); err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not resume the saved game, because %s", err))
+	}
+}
+
+// restoreGame replaces the game on the baize with a saved one, if it fits
+func (b *Baize) restoreGame(sg *SavedGame) error {
+	if err := b.checkSavedGame(sg); err != nil {
+		log.Println("ignoring saved game of", sg.Variant, "because", err)
+		return err
+	}
+	b.Reset()
+	b.script.Stock().seed = sg.Seed
+	b.moves, b.elapsed, b.undos, b.started = sg.Moves, sg.Elapsed, sg.Undos, sg.Started
+	b.scoring = sg.Scoring
+	b.SetUndoStack(sg.UndoStack)
+	b.branches = sg.Branches
+	return nil
+}
+
+// SetSavedGames is given the games that were in progress when the app last closed,
+// and resumes the one for the current variant. seq is the number of the last journal entry
+func (b *Baize) SetSavedGames(games []*SavedGame, seq int64) {
+	b.savedGames = make(map[string]*SavedGame, len(games))
+	for _, sg := range games {
+		b.savedGames[sg.key()] = sg
+	}
+	b.resumeGame()
+	b.startJournal(seq)
+}
+
+// savableGames returns all the games in progress, including the current one, in variant order
+func (b *Baize) savableGames() []*SavedGame {
+	var games []*SavedGame
+	for _, sg := range b.savedGames {
+		games = append(games, sg)
+	}
+	if b.inProgress() {
+		games = append(games, b.newSavedGame())
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].Variant < games[j].Variant })
+	return games
+}
+
+// snapshot returns all the games in progress, ready to be saved
+func (b *Baize) snapshot() *savedGamesFile {
+	return &savedGamesFile{Seq: b.journalSeq, Games: b.savableGames()}
+}
+
+// unmarshalSavedGames decodes saved games, migrating older formats to the current one,
+// and returns them with the sequence number of the last journal entry they include
+func unmarshalSavedGames(bytes []byte) ([]*SavedGame, int64, error) {
+	var sgf savedGamesFile
+	// the version 1 map of variant names is also an object, so don't let it through
+	dec := json.NewDecoder(strings.NewReader(string(bytes)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&sgf); err == nil {
+		for _, sg := range sgf.Games {
+			sg.migrate()
+		}
+		return sgf.Games, sgf.Seq, nil
+	}
+
+	var games []*SavedGame
+	// a version 0 undo stack is also a list, but it's elements don't have a Version
+	if err := json.Unmarshal(bytes, &games); err == nil && (len(games) == 0 || games[0].Version > 0) {
+		for _, sg := range games {
+			sg.migrate()
+		}
+		return games, 0, nil
+	}
+
+	var undoStacks map[string][]*SavableBaize
+	if err := json.Unmarshal(bytes, &undoStacks); err != nil {
+		var undoStack []*SavableBaize
+		if err := json.Unmarshal(bytes, &undoStack); err != nil {
+			return nil, 0, err
+		}
+		undoStacks = map[string][]*SavableBaize{ThePreferences.Variant: undoStack}
+	}
+	games = nil
+	for v, undoStack := range undoStacks {
+		if len(undoStack) == 0 {
+			continue
+		}
+		sg := &SavedGame{Version: 1, Variant: v, UndoStack: undoStack}
+		sg.migrate()
+		games = append(games, sg)
+	}
+	return games, 0, nil
+}
+
+// Autosave saves the games in progress, if anything has changed since the last time
+func (b *Baize) Autosave() {
+	if NoGameSave || !b.unsaved {
+		return
+	}
+	b.Save()
+	b.unsaved = false
+}
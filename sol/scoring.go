@@ -0,0 +1,199 @@
+package sol
+
+import (
+	"fmt"
+	"time"
+
+	"oddstream.games/gosol/util"
+)
+
+// A score is worked out from the undo stack, by comparing each position with the one before,
+// so an undone move takes its points away with it. What each thing is worth is up to the ScoringModel,
+// chosen by the user in the settings drawer. Each deal keeps the model it was dealt with,
+// so a new choice starts with the next deal
+
+// ScoringModel turns a tally of what has happened in a game into a score
+type ScoringModel interface {
+	// Score for the game so far
+	Score(t *tally) int
+	// Cumulative is true if scores are carried over from one deal to the next, like Vegas
+	Cumulative() bool
+	// RecycleLimit is the number of times the stock may be recycled, given the variant's own rules
+	RecycleLimit(draw, recycles int) int
+	// Format a score (or running total) for the statusbar
+	Format(score int) string
+}
+
+// tally counts the things that scores are made from
+type tally struct {
+	ToFoundation    int // cards moved onto a foundation
+	FromFoundation  int // cards moved back off a foundation
+	WasteToTableau  int // moves from the waste to a tableau
+	Flips           int // tableau cards turned face up
+	Recycles        int // times the waste was turned back into the stock
+	FoundationCards int // cards on the foundations now
+	Draw            int // cards dealt from the stock at a time
+	Undos           int
+	Elapsed         time.Duration
+	Complete        bool
+}
+
+// ScoringModels are the ways of keeping score that the user can choose from
+var ScoringModels = map[string]ScoringModel{
+	"None":             &NoScoring{},
+	"Standard":         &StandardScoring{},
+	"Vegas":            &VegasScoring{},
+	"Foundation cards": &FoundationScoring{},
+}
+
+// ScoringModelNames are the keys of ScoringModels, in the order to show them
+var ScoringModelNames = []string{"None", "Standard", "Vegas", "Foundation cards"}
+
+// NoScoring doesn't keep score, which is how it's always been
+type NoScoring struct{}
+
+func (*NoScoring) Score(*tally) int                    { return 0 }
+func (*NoScoring) Cumulative() bool                    { return false }
+func (*NoScoring) RecycleLimit(draw, recycles int) int { return recycles }
+func (*NoScoring) Format(score int) string             { return "" }
+
+// StandardScoring is the scoring of the Solitaire that came with Windows
+type StandardScoring struct{}
+
+func (*StandardScoring) Score(t *tally) int {
+	score := 5*t.WasteToTableau + 10*t.ToFoundation - 15*t.FromFoundation + 5*t.Flips
+	if t.Draw == 3 {
+		// the first three passes through the stock are free
+		score -= 20 * util.Max(t.Recycles-2, 0)
+	} else {
+		score -= 100 * t.Recycles
+	}
+	score -= 2 * int(t.Elapsed/(10*time.Second))
+	score -= 2 * t.Undos
+	if score < 0 {
+		score = 0
+	}
+	if secs := int(t.Elapsed / time.Second); t.Complete && secs >= 30 {
+		score += 700000 / secs
+	}
+	return score
+}
+
+func (*StandardScoring) Cumulative() bool                    { return false }
+func (*StandardScoring) RecycleLimit(draw, recycles int) int { return recycles }
+func (*StandardScoring) Format(score int) string             { return fmt.Sprint(score) }
+
+// VegasScoring buys each deal for $52, and pays $5 for every card on a foundation.
+// The money is carried over from one deal to the next, and the stock can only be gone through
+// once when drawing one card, or three times when drawing three
+type VegasScoring struct{}
+
+func (*VegasScoring) Score(t *tally) int { return 5*t.FoundationCards - 52 }
+func (*VegasScoring) Cumulative() bool   { return true }
+
+func (*VegasScoring) RecycleLimit(draw, recycles int) int {
+	if draw == 3 {
+		return util.Min(recycles, 2)
+	}
+	return 0
+}
+
+func (*VegasScoring) Format(score int) string {
+	if score < 0 {
+		return fmt.Sprintf("-$%d", -score)
+	}
+	return fmt.Sprintf("$%d", score)
+}
+
+// FoundationScoring is just the number of cards on the foundations
+type FoundationScoring struct{}
+
+func (*FoundationScoring) Score(t *tally) int                  { return t.FoundationCards }
+func (*FoundationScoring) Cumulative() bool                    { return false }
+func (*FoundationScoring) RecycleLimit(draw, recycles int) int { return recycles }
+func (*FoundationScoring) Format(score int) string             { return fmt.Sprint(score) }
+
+// scoringModel is the one the current deal is scored with; switching half way through a game
+// would let it be played under one model's rules (like Vegas' limit on recycles) and scored under another's
+func (b *Baize) scoringModel() ScoringModel {
+	if sm, ok := ScoringModels[b.scoring]; ok {
+		return sm
+	}
+	return ScoringModels["None"]
+}
+
+// tallyUndoStack counts what has happened in the undo stack
+func tallyUndoStack(undoStack []*SavableBaize) *tally {
+	t := &tally{}
+	for i := 1; i < len(undoStack); i++ {
+		prev, next := undoStack[i-1], undoStack[i]
+		if len(prev.Piles) != len(next.Piles) {
+			continue
+		}
+		var fromWaste, toTableau bool
+		for j, np := range next.Piles {
+			pp := prev.Piles[j]
+			d := len(np.Cards) - len(pp.Cards)
+			switch np.Category {
+			case "Foundation":
+				if d > 0 {
+					t.ToFoundation += d
+				} else {
+					t.FromFoundation -= d
+				}
+			case "Waste":
+				fromWaste = fromWaste || d < 0
+			case "Tableau":
+				toTableau = toTableau || d > 0
+				for k := 0; k < len(np.Cards) && k < len(pp.Cards); k++ {
+					if pp.Cards[k].Prone() && !np.Cards[k].Prone() {
+						t.Flips++
+					}
+				}
+			}
+		}
+		if fromWaste && toTableau {
+			t.WasteToTableau++
+		}
+		if next.Recycles < prev.Recycles {
+			t.Recycles += prev.Recycles - next.Recycles
+		}
+	}
+	if len(undoStack) > 0 {
+		for _, sp := range undoStack[len(undoStack)-1].Piles {
+			if sp.Category == "Foundation" {
+				t.FoundationCards += len(sp.Cards)
+			}
+		}
+	}
+	return t
+}
+
+// Score works out the score of the current game, with the chosen scoring model
+func (b *Baize) Score() int {
+	t := b.tally
+	if t == nil {
+		t = tallyUndoStack(b.undoStack)
+	}
+	t.Draw = 1
+	if kl, ok := b.script.(*Klondike); ok {
+		t.Draw = kl.draw
+	}
+	t.Undos = b.undos
+	t.Elapsed = b.elapsed
+	t.Complete = b.Complete()
+	return b.scoringModel().Score(t)
+}
+
+// scoreStatus is what to show in the statusbar, if anything
+func (b *Baize) scoreStatus() string {
+	sm := b.scoringModel()
+	if _, ok := sm.(*NoScoring); ok {
+		return ""
+	}
+	score := b.Score()
+	if stats, ok := TheStatistics.StatsMap[b.LongVariantName()]; ok && sm.Cumulative() {
+		score += stats.Bankroll
+	}
+	return "SCORE: " + sm.Format(score)
+}
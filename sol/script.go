@@ -16,6 +16,7 @@ type ScriptBase struct {
 	cells       []*Cell
 	discards    []*Discard
 	foundations []*Foundation
+	grid        []*GridCell
 	reserves    []*Reserve
 	tableaux    []*Tableau
 }
@@ -32,6 +33,10 @@ func (sb ScriptBase) Discards() []*Discard {
 	return sb.discards
 }
 
+func (sb ScriptBase) Grid() []*GridCell {
+	return sb.grid
+}
+
 func (sb ScriptBase) Reserves() []*Reserve {
 	return sb.reserves
 }
@@ -52,6 +57,7 @@ type VariantInfo struct {
 	windowShape string
 	wikipedia   string
 	relaxable   bool
+	autoPlay    bool // the game plays itself once dealt, the user just watches
 }
 
 // You can't use functions as keys in maps : the key type must be comparable
@@ -75,26 +81,78 @@ type ScriptInterface interface {
 	Cells() []*Cell
 	Discards() []*Discard
 	Foundations() []*Foundation
+	Grid() []*GridCell
 	Reserves() []*Reserve
 	Stock() *Stock
 	Tableaux() []*Tableau
 	Waste() *Waste
 }
 
+// AutoPlayer is implemented by scripts that can make their own moves, either because
+// the game plays itself (VariantInfo.autoPlay) or to show off in DemoMode.
+// AutoMove makes one move and returns true, or returns false if there are no more moves to make
+type AutoPlayer interface {
+	AutoMove() bool
+}
+
 var Variants = map[string]ScriptInterface{
-	"Agnes Bernauer":      &Agnes{},
-	"American Toad":       &Toad{},
-	"Australian":          &Australian{},
-	"Baker's Dozen":       &BakersDozen{},
+	"Agnes Bernauer": &Agnes{},
+	"American Toad":  &Toad{},
+	"Accordion":      &Accordion{},
+	"Australian":     &Australian{},
+	"Baker's Dozen":  &BakersDozen{},
+	"Blue Moon":      &Montana{moon: true},
+	"Beleaguered Castle": &BeleagueredCastle{
+		wikipedia: "https://en.wikipedia.org/wiki/Beleaguered_Castle",
+		dealAces:  true,
+	},
+	"Streets and Alleys": &BeleagueredCastle{
+		wikipedia: "https://en.wikipedia.org/wiki/Beleaguered_Castle",
+	},
+	"Citadel": &BeleagueredCastle{
+		wikipedia:    "https://en.wikipedia.org/wiki/Beleaguered_Castle",
+		dealToFounds: true,
+	},
+	"Fortress": &BeleagueredCastle{
+		wikipedia:      "https://en.wikipedia.org/wiki/Fortress_(solitaire)",
+		rows:           5,
+		dealToFounds:   true,
+		moveType:       MOVE_ONE,
+		tabCompareFunc: CardPair.Compare_UpOrDownSuit,
+	},
 	"Canfield":            &Canfield{draw: 3, recycles: 32767, tabCompareFunc: CardPair.Compare_DownAltColorWrap},
 	"Storehouse":          &Canfield{draw: 1, recycles: 2, tabCompareFunc: CardPair.Compare_DownSuitWrap, variant: "storehouse"},
 	"Duchess":             &Duchess{},
 	"Klondike":            &Klondike{draw: 1, recycles: 2},
 	"Klondike Draw Three": &Klondike{draw: 3, recycles: 9},
+	"Klondike Jokers":     &Klondike{draw: 1, recycles: 2, jokers: 2},
 	"Thoughtful":          &Klondike{draw: 1, recycles: 32767, thoughtful: true},
 	"Easy":                &Easy{},
 	"Eight Off":           &EightOff{},
 	"Freecell":            &Freecell{},
+	"Fan": &Fan{
+		wikipedia:  "https://en.wikipedia.org/wiki/Fan_(solitaire)",
+		emptyLabel: "K",
+	},
+	"La Belle Lucie": &Fan{
+		wikipedia:  "https://en.wikipedia.org/wiki/La_Belle_Lucie",
+		redeals:    2,
+		emptyLabel: "x",
+	},
+	"Trefoil": &Fan{
+		wikipedia:  "https://en.wikipedia.org/wiki/La_Belle_Lucie",
+		redeals:    2,
+		dealAces:   true,
+		emptyLabel: "x",
+	},
+	"Shamrocks": &Fan{
+		wikipedia:      "https://en.wikipedia.org/wiki/La_Belle_Lucie",
+		redeals:        2,
+		buryKings:      true,
+		emptyLabel:     "x",
+		maxTabLen:      3,
+		tabCompareFunc: CardPair.Compare_UpOrDown,
+	},
 	"Forty Thieves": &FortyThieves{
 		founds:      []int{3, 4, 5, 6, 7, 8, 9, 10},
 		tabs:        []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
@@ -175,12 +233,18 @@ var Variants = map[string]ScriptInterface{
 		tabs:        []int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14},
 		cardsPerTab: 5,
 	},
+	"Montana":           &Montana{},
+	"Red Moon":          &Montana{moon: true, redMoon: true},
 	"Penguin":           &Penguin{},
 	"Scorpion":          &Scorpion{},
 	"Simple Simon":      &SimpleSimon{},
+	"Clock":             &Clock{},
+	"Calculation":       &Calculation{},
+	"Betsy Ross":        &Calculation{betsyRoss: true},
 	"Spider One Suit":   &Spider{packs: 8, suits: 1},
 	"Spider Two Suits":  &Spider{packs: 4, suits: 2},
 	"Spider Four Suits": &Spider{packs: 2, suits: 4},
+	"Spider Jokers":     &Spider{packs: 2, suits: 4, jokers: 1},
 	"Whitehead":         &Whitehead{},
 	"Yukon":             &Yukon{},
 	"Yukon Cells":       &Yukon{extraCells: 2},
@@ -192,14 +256,18 @@ var VariantGroups = map[string][]string{
 	// "All" added dynamically by func init()
 	// don't have Agnes here (as a group) because it would come before All
 	// and Agnes Sorel is retired because it's just too hard
-	"> Klondike":      {"Klondike", "Klondike Draw Three", "Thoughtful", "Whitehead"},
-	"> Forty Thieves": {"Forty Thieves", "Number Ten", "Red and Black", "Indian", "Rank and File", "Sixty Thieves", "Josephine", "Limited", "Forty and Eight", "Lucas", "Busy Aces", "Maria", "Streets"},
-	"> Spider":        {"Spider One Suit", "Spider Two Suits", "Spider Four Suits", "Scorpion"},
-	"> Canfield":      {"Canfield", "Storehouse", "Duchess", "American Toad"},
-	"> Freecell":      {"Freecell", "Eight Off"},
-	"> Yukon":         {"Yukon", "Yukon Cells", "Alaska"},
-	"> Puzzlers":      {"Penguin", "Simple Simon", "Baker's Dozen", "Freecell"},
-	"> Places":        {"Australian", "Yukon", "Klondike", "Crimean", "Ukranian"},
+	"> Klondike":           {"Klondike", "Klondike Draw Three", "Klondike Jokers", "Thoughtful", "Whitehead"},
+	"> Forty Thieves":      {"Forty Thieves", "Number Ten", "Red and Black", "Indian", "Rank and File", "Sixty Thieves", "Josephine", "Limited", "Forty and Eight", "Lucas", "Busy Aces", "Maria", "Streets"},
+	"> Spider":             {"Spider One Suit", "Spider Two Suits", "Spider Four Suits", "Spider Jokers", "Scorpion"},
+	"> Calculation":        {"Calculation", "Betsy Ross"},
+	"> Canfield":           {"Canfield", "Storehouse", "Duchess", "American Toad"},
+	"> Freecell":           {"Freecell", "Eight Off"},
+	"> Yukon":              {"Yukon", "Yukon Cells", "Alaska"},
+	"> Puzzlers":           {"Penguin", "Simple Simon", "Baker's Dozen", "Freecell"},
+	"> Places":             {"Australian", "Yukon", "Klondike", "Crimean", "Ukranian"},
+	"> Fan":                {"Fan", "La Belle Lucie", "Trefoil", "Shamrocks"},
+	"> Montana":            {"Montana", "Blue Moon", "Red Moon"},
+	"> Beleaguered Castle": {"Beleaguered Castle", "Streets and Alleys", "Citadel", "Fortress"},
 }
 
 func init() {
@@ -236,6 +304,9 @@ func Compare_Empty(p Pile, c *Card) (bool, error) {
 		if p.Label() == "x" {
 			return false, errors.New("Cannot move cards there")
 		}
+		if c.Wild() {
+			return true, nil // a joker can start any pile that can be started
+		}
 		ord := util.OrdinalToShortString(c.Ordinal())
 		if ord != p.Label() {
 			return false, fmt.Errorf("Can only accept %s, not %s", util.ShortOrdinalToLongOrdinal(p.Label()), util.ShortOrdinalToLongOrdinal(ord))
@@ -263,23 +334,106 @@ func RecycleWasteToStock(waste Pile, stock Pile) {
 	}
 }
 
+// RedealTableaux gathers up the cards in the tableaux, shuffles them,
+// and deals them back in groups of cardsPerPile, using one of the redeals
+func RedealTableaux(tableaux []*Tableau, stock *Stock, cardsPerPile int) {
+	if TheBaize.Redeals() == 0 {
+		TheUI.Toast("No more redeals")
+		return
+	}
+	for _, t := range tableaux {
+		for !t.Empty() {
+			MoveCard(t, stock)
+		}
+	}
+	stock.Reshuffle()
+	for _, t := range tableaux {
+		for i := 0; i < cardsPerPile; i++ {
+			if MoveCard(stock, t) == nil {
+				break
+			}
+		}
+	}
+	TheBaize.SetRedeals(TheBaize.Redeals() - 1)
+	toastRedeals()
+}
+
+func toastRedeals() {
+	switch {
+	case TheBaize.redeals == 0:
+		TheUI.Toast("No more redeals")
+	case TheBaize.redeals == 1:
+		TheUI.Toast(fmt.Sprintf("%d redeal remaining", TheBaize.Redeals()))
+	case TheBaize.redeals < 10:
+		TheUI.Toast(fmt.Sprintf("%d redeals remaining", TheBaize.Redeals()))
+	}
+}
+
 func UnsortedPairs(pile Pile, fn func(CardPair) (bool, error)) int {
 	if pile.Len() < 2 {
 		return 0
 	}
 	var unsorted int
-	for _, pair := range NewCardPairs(pile.Cards()) {
+	cards := pile.Cards()
+	for i, pair := range NewCardPairs(cards) {
 		if pair.EitherProne() {
 			unsorted++
 		} else {
 			if ok, _ := fn(pair); !ok {
 				unsorted++
+			} else if i > 0 && pair.c1.Wild() && !jokerFits(cards[i-1], pair.c2, fn) {
+				unsorted++
 			}
 		}
 	}
 	return unsorted
 }
 
+// TailConformant checks that each card in the tail follows the one before it,
+// and that every joker in the middle of the tail could be replaced by a real card
+func TailConformant(tail []*Card, fn func(CardPair) (bool, error)) (bool, error) {
+	for _, pair := range NewCardPairs(tail) {
+		if ok, err := fn(pair); !ok {
+			return false, err
+		}
+	}
+	for i := 1; i < len(tail)-1; i++ {
+		if tail[i].Wild() && !jokerFits(tail[i-1], tail[i+1], fn) {
+			return false, errors.New("No card could take the place of that joker")
+		}
+	}
+	return true, nil
+}
+
+// AppendConformant checks that a tail can go on the end of a non-empty pile;
+// if the pile ends with a joker, there must still be a card it could stand in for
+func AppendConformant(dst Pile, tail []*Card, fn func(CardPair) (bool, error)) (bool, error) {
+	var cards []*Card = []*Card{dst.Peek()}
+	if n := dst.Len(); n > 1 && cards[0].Wild() && !dst.Get(n-2).Prone() {
+		cards = append([]*Card{dst.Get(n - 2)}, cards...)
+	}
+	return TailConformant(append(cards, tail...), fn)
+}
+
+// jokerFits returns true if there is a card that could go between c1 and c2
+func jokerFits(c1, c2 *Card, fn func(CardPair) (bool, error)) bool {
+	if c1.Wild() || c2.Wild() {
+		return true // a run of jokers can be anything
+	}
+	for suit := CLUB; suit <= SPADE; suit++ {
+		for ord := 1; ord < 14; ord++ {
+			c := &Card{ID: NewCardID(0, suit, ord)}
+			if ok, _ := fn(CardPair{c1, c}); !ok {
+				continue
+			}
+			if ok, _ := fn(CardPair{c, c2}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type CardPair struct {
 	c1, c2 *Card
 }
@@ -288,6 +442,11 @@ func (cp CardPair) EitherProne() bool {
 	return cp.c1.Prone() || cp.c2.Prone()
 }
 
+// Wild returns true if either card is a wild joker, which can follow or be followed by anything
+func (cp CardPair) Wild() bool {
+	return cp.c1.Wild() || cp.c2.Wild()
+}
+
 type CardPairs []CardPair
 
 func NewCardPairs(cards []*Card) []CardPair {
@@ -311,6 +470,9 @@ func (cpairs CardPairs) Print() {
 }
 
 func (cp CardPair) Compare_Up() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Ordinal()+1 != cp.c2.Ordinal() {
 		return false, errors.New("Cards must be in ascending sequence")
 	}
@@ -318,13 +480,29 @@ func (cp CardPair) Compare_Up() (bool, error) {
 }
 
 func (cp CardPair) Compare_Down() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Ordinal() != cp.c2.Ordinal()+1 {
 		return false, errors.New("Cards must be in descending sequence")
 	}
 	return true, nil
 }
 
+func (cp CardPair) Compare_UpOrDown() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
+	if cp.c1.Ordinal()+1 != cp.c2.Ordinal() && cp.c1.Ordinal() != cp.c2.Ordinal()+1 {
+		return false, errors.New("Cards must go up or down in rank")
+	}
+	return true, nil
+}
+
 func (cp CardPair) Compare_DownColor() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Black() != cp.c2.Black() {
 		return false, errors.New("Cards must be the same color")
 	}
@@ -332,6 +510,9 @@ func (cp CardPair) Compare_DownColor() (bool, error) {
 }
 
 func (cp CardPair) Compare_DownAltColor() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Black() == cp.c2.Black() {
 		return false, errors.New("Cards must be in alternating colors")
 	}
@@ -339,6 +520,9 @@ func (cp CardPair) Compare_DownAltColor() (bool, error) {
 }
 
 func (cp CardPair) Compare_DownColorWrap() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Black() != cp.c2.Black() {
 		return false, errors.New("Cards must be the same color")
 	}
@@ -352,6 +536,9 @@ func (cp CardPair) Compare_DownColorWrap() (bool, error) {
 }
 
 func (cp CardPair) Compare_DownAltColorWrap() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Black() == cp.c2.Black() {
 		return false, errors.New("Cards must be in alternating colors")
 	}
@@ -365,6 +552,9 @@ func (cp CardPair) Compare_DownAltColorWrap() (bool, error) {
 }
 
 func (cp CardPair) Compare_UpAltColor() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Black() == cp.c2.Black() {
 		return false, errors.New("Cards must be in alternating colors")
 	}
@@ -372,6 +562,9 @@ func (cp CardPair) Compare_UpAltColor() (bool, error) {
 }
 
 func (cp CardPair) Compare_UpSuit() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() != cp.c2.Suit() {
 		return false, errors.New("Cards must be the same suit")
 	}
@@ -379,6 +572,9 @@ func (cp CardPair) Compare_UpSuit() (bool, error) {
 }
 
 func (cp CardPair) Compare_DownSuit() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() != cp.c2.Suit() {
 		return false, errors.New("Cards must be the same suit")
 	}
@@ -386,13 +582,54 @@ func (cp CardPair) Compare_DownSuit() (bool, error) {
 }
 
 func (cp CardPair) Compare_DownOtherSuit() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() == cp.c2.Suit() {
 		return false, errors.New("Cards must not be the same suit")
 	}
 	return cp.Compare_Down()
 }
 
+func (cp CardPair) Compare_UpOrDownSuit() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
+	if cp.c1.Suit() != cp.c2.Suit() {
+		return false, errors.New("Cards must be the same suit")
+	}
+	if cp.c1.Ordinal()+1 != cp.c2.Ordinal() && cp.c1.Ordinal() != cp.c2.Ordinal()+1 {
+		return false, errors.New("Cards must go up or down in rank")
+	}
+	return true, nil
+}
+
+// Compare_UpStep is for building by adding step to the ordinal, wrapping round from King to Ace,
+// regardless of suit (eg Calculation)
+func (cp CardPair) Compare_UpStep(step int) (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
+	if ((cp.c1.Ordinal()-1+step)%13+13)%13+1 != cp.c2.Ordinal() {
+		return false, fmt.Errorf("Cards must go up in steps of %d", step)
+	}
+	return true, nil
+}
+
+func (cp CardPair) Compare_SuitOrRank() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
+	if cp.c1.Suit() != cp.c2.Suit() && cp.c1.Ordinal() != cp.c2.Ordinal() {
+		return false, errors.New("Cards must be the same suit or the same rank")
+	}
+	return true, nil
+}
+
 func (cp CardPair) Compare_UpSuitWrap() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() != cp.c2.Suit() {
 		return false, errors.New("Cards must be the same suit")
 	}
@@ -406,6 +643,9 @@ func (cp CardPair) Compare_UpSuitWrap() (bool, error) {
 }
 
 func (cp CardPair) Compare_DownSuitWrap() (bool, error) {
+	if cp.Wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() != cp.c2.Suit() {
 		return false, errors.New("Cards must be the same suit")
 	}
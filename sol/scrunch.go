@@ -112,7 +112,8 @@ func (self *Core) Scrunch() {
 		// maxPileSize = TheBaize.WindowHeight - scpos.Y + util.Abs(TheBaize.dragOffset.Y)
 		maxPileSize = TheBaize.WindowHeight - self.ScreenPos().Y + (CardHeight / 2)
 	case FAN_LEFT:
-		maxPileSize = self.ScreenPos().X
+		// the pile grows leftwards from the right edge of it's first card
+		maxPileSize = self.ScreenPos().X + CardWidth
 	case FAN_RIGHT:
 		// baize->dragOffset is always -ve
 		// maxPileSize = TheBaize.WindowWidth - scpos.X + util.Abs(TheBaize.dragOffset.X)
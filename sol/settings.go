@@ -1,5 +1,7 @@
 package sol
 
+import "oddstream.games/gosol/ui"
+
 func ShowSettingsDrawer() {
 	// TODO this pattern is well ugly
 	// consider using callbacks so UI can query each setting
@@ -11,5 +13,8 @@ func ShowSettingsDrawer() {
 		"MirrorBaize": ThePreferences.MirrorBaize,
 		"Mute":        ThePreferences.Mute,
 	}
-	TheUI.ShowSettingsDrawer(booleanSettings)
+	var spinnerSettings = []ui.SpinnerSetting{
+		{Text: "Scoring", Options: ScoringModelNames, Current: ThePreferences.Scoring, RequestType: "Scoring"},
+	}
+	TheUI.ShowSettingsDrawer(booleanSettings, spinnerSettings)
 }
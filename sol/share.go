@@ -0,0 +1,105 @@
+//go:build !js
+
+package sol
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// canPickFiles is false, because there's nothing here to pick a file with; use the -importstats flag
+const canPickFiles = false
+
+// shareCustomVariant writes the variant to a file of it's own, which can be given to someone else
+// and loaded with the -import flag
+func shareCustomVariant(cv *CustomVariant) {
+	bytes, err := json.MarshalIndent(cv, "", "\t")
+	if err != nil {
+		log.Println("shareCustomVariant Marshal", err)
+		return
+	}
+	fname := strings.ToLower(strings.ReplaceAll(cv.Name, " ", "-")) + ".json"
+	if err := TheStorage.Save(fname, bytes); err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not save %s: %s", fname, err))
+		return
+	}
+	TheUI.Toast("Saved " + TheStorage.Where(fname))
+}
+
+// importCustomVariant adds the custom variant in a shared file, and returns it's name,
+// which may be different to the one in the file if that name is already taken
+func importCustomVariant(path string) string {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		log.Println(err)
+		return ""
+	}
+	var cv CustomVariant
+	if err = json.Unmarshal(bytes, &cv); err != nil {
+		log.Println("importCustomVariant Unmarshal", err)
+		return ""
+	}
+	added, err := addCustomVariant(&cv)
+	if err != nil {
+		log.Println("cannot import", path, err)
+		return ""
+	}
+	println("imported", path, "as", added.Name)
+	return added.Name
+}
+
+// ExportHistory writes the history of finished games to a file, as CSV or JSON depending on it's extension
+func ExportHistory(path string) error {
+	openStorage()
+	bytes, err := exportHistory(strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0644)
+}
+
+// exportStatisticsFile writes a portable copy of the statistics next to statistics.json,
+// to be taken to another device and imported there with the -importstats flag
+func exportStatisticsFile() {
+	bytes, err := TheStatistics.exportStatistics()
+	if err != nil {
+		log.Println("exportStatisticsFile", err)
+		return
+	}
+	fname := "statistics-" + TheStatistics.Device + ".json"
+	if err := TheStorage.Save(fname, bytes); err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not save %s: %s", fname, err))
+		return
+	}
+	TheUI.Toast("Saved " + TheStorage.Where(fname))
+}
+
+// pickStatisticsFile isn't possible here
+func pickStatisticsFile() {
+	TheUI.Toast("Start with -importstats file.json to import statistics")
+}
+
+// importStatisticsFile merges the statistics exported from another device
+func importStatisticsFile(path string) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not read %s", path))
+		log.Println(err)
+		return
+	}
+	TheStatistics.mergeStatistics(bytes)
+}
+
+// ExportStatistics writes a portable copy of the statistics to a file
+func ExportStatistics(path string) error {
+	openStorage()
+	bytes, err := NewStatistics().exportStatistics()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0644)
+}
@@ -0,0 +1,84 @@
+package sol
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"syscall/js"
+)
+
+// canPickFiles is true, because the browser has a file picker
+const canPickFiles = true
+
+// download has the browser download some JSON as a file
+func download(fname string, bytes []byte) {
+	doc := js.Global().Get("document")
+	blob := js.Global().Get("Blob").New([]interface{}{string(bytes)}, map[string]interface{}{"type": "application/json"})
+	url := js.Global().Get("URL").Call("createObjectURL", blob)
+	a := doc.Call("createElement", "a")
+	a.Set("href", url)
+	a.Set("download", fname)
+	a.Call("click")
+	js.Global().Get("URL").Call("revokeObjectURL", url)
+}
+
+// shareCustomVariant has the browser download the variant as a small file
+func shareCustomVariant(cv *CustomVariant) {
+	bytes, err := json.MarshalIndent(cv, "", "\t")
+	if err != nil {
+		log.Println("shareCustomVariant().Marshal() error", err)
+		return
+	}
+	download(strings.ToLower(strings.ReplaceAll(cv.Name, " ", "-"))+".json", bytes)
+}
+
+// importCustomVariant isn't possible in a browser, which can't read files by name
+func importCustomVariant(path string) string {
+	log.Println("cannot import", path, "in a browser")
+	return ""
+}
+
+// exportStatisticsFile has the browser download a portable copy of the statistics
+func exportStatisticsFile() {
+	bytes, err := TheStatistics.exportStatistics()
+	if err != nil {
+		log.Println("exportStatisticsFile", err)
+		return
+	}
+	download("statistics-"+TheStatistics.Device+".json", bytes)
+}
+
+// pickStatisticsFile has the user pick an export from another device.
+// The file is read in the background, and handed to the game loop to be merged
+func pickStatisticsFile() {
+	input := js.Global().Get("document").Call("createElement", "input")
+	input.Set("type", "file")
+	input.Set("accept", ".json,application/json")
+	var onChange js.Func
+	onChange = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		onChange.Release()
+		files := input.Get("files")
+		if files.Length() == 0 {
+			return nil
+		}
+		var onText js.Func
+		onText = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			onText.Release()
+			select {
+			case importedStatistics <- []byte(args[0].String()):
+			default:
+				log.Println("already importing statistics")
+			}
+			return nil
+		})
+		files.Index(0).Call("text").Call("then", onText)
+		return nil
+	})
+	input.Call("addEventListener", "change", onChange)
+	input.Call("click")
+}
+
+// importStatisticsFile isn't possible in a browser, which can't read files by name
+func importStatisticsFile(path string) {
+	log.Println("cannot import", path, "in a browser")
+}
@@ -0,0 +1,264 @@
+package sol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fogleman/gg"
+	"oddstream.games/gosol/ui"
+	"oddstream.games/gosol/util"
+)
+
+// The user can save a game in a named slot, and come back to it later.
+// Slots are kept apart from saved.json, which only holds the one game in progress for each variant,
+// and nothing in a slot changes unless the user saves over it or deletes it
+
+const (
+	thumbnailWidth  = 96
+	thumbnailHeight = 72
+)
+
+var (
+	slotName          string // typed into the saved games drawer, for the next save
+	slotDeletePending string // slot that has had it's delete tapped once
+)
+
+// SaveSlot is a game that has been saved by name
+type SaveSlot struct {
+	Name      string
+	Variant   string
+	Saved     time.Time
+	Percent   int
+	Thumbnail []byte `json:",omitempty"` // PNG
+	Game      *SavedGame
+}
+
+func loadSaveSlots() []*SaveSlot {
+	var slots []*SaveSlot
+	loadData("slots.json", func(bytes []byte) error { return json.Unmarshal(bytes, &slots) })
+	for _, ss := range slots {
+		if ss.Game != nil {
+			ss.Game.migrate()
+		}
+	}
+	return slots
+}
+
+func saveSaveSlots(slots []*SaveSlot) {
+	// not indented, because of the thumbnails and undo stacks
+	bytes, err := json.Marshal(slots)
+	if err != nil {
+		log.Println("saveSaveSlots Marshal", err)
+		return
+	}
+	saveData("slots.json", bytes)
+}
+
+// thumbnail draws a little picture of the baize, good enough to tell saved games apart
+func (b *Baize) thumbnail() []byte {
+	var maxX, maxY int
+	for _, p := range b.piles {
+		if p.Hidden() {
+			continue
+		}
+		maxX = util.Max(maxX, p.Slot().X)
+		maxY = util.Max(maxY, p.Slot().Y)
+	}
+	// leave room below the lowest piles for their cards to fan down
+	cellW := float64(thumbnailWidth) / float64(maxX+1)
+	cellH := float64(thumbnailHeight) / float64(maxY+3)
+	cardW, cardH := cellW*0.8, util.Min(int(cellH*0.9), int(cellW*1.2))
+
+	dc := gg.NewContext(thumbnailWidth, thumbnailHeight)
+	dc.SetColor(ExtendedColors[ThePreferences.BaizeColor])
+	dc.Clear()
+	for _, p := range b.piles {
+		if p.Hidden() {
+			continue
+		}
+		x := float64(p.Slot().X)*cellW + (cellW-cardW)/2
+		y := float64(p.Slot().Y) * cellH
+		if p.Len() == 0 {
+			dc.SetRGBA(1, 1, 1, 0.3)
+			dc.DrawRectangle(x, y, cardW, float64(cardH))
+			dc.Stroke()
+			continue
+		}
+		cards := p.Cards()
+		switch p.FanType() {
+		case FAN_DOWN, FAN_DOWN3:
+		default:
+			cards = cards[len(cards)-1:]
+		}
+		for _, c := range cards {
+			if c.Prone() {
+				dc.SetColor(ExtendedColors[ThePreferences.CardBackColor])
+			} else {
+				dc.SetColor(ExtendedColors[ThePreferences.CardFaceColor])
+			}
+			dc.DrawRectangle(x, y, cardW, float64(cardH))
+			dc.Fill()
+			if !c.Prone() {
+				dc.SetColor(c.Color())
+				dc.DrawRectangle(x+1, y+1, cardW/3, 2)
+				dc.Fill()
+			}
+			y += 3
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dc.Image()); err != nil {
+		log.Println("cannot make thumbnail", err)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// newSlotName makes up a name for a slot that isn't already taken
+func newSlotName(slots []*SaveSlot) string {
+	var name string
+	for n := 1; ; n++ {
+		name = fmt.Sprintf("%s %d", ThePreferences.Variant, n)
+		if findSaveSlot(slots, name) == nil {
+			return name
+		}
+	}
+}
+
+// SaveSlot saves the current game under the name typed into the saved games drawer,
+// or a made up one if nothing was typed. Saving under the name of an existing slot saves over it
+func (b *Baize) SaveSlot() {
+	if len(b.undoStack) < 2 {
+		TheUI.Toast("Nothing to save yet")
+		return
+	}
+	slots := loadSaveSlots()
+	name := strings.TrimSpace(slotName)
+	if name == "" {
+		name = newSlotName(slots)
+	}
+	slotName = ""
+	sg := b.newSavedGame()
+	// the undo stack will carry on growing, so take a copy of it as it is now
+	sg.UndoStack = append([]*SavableBaize(nil), b.undoStack...)
+	ss := &SaveSlot{
+		Name:      name,
+		Variant:   ThePreferences.Variant,
+		Saved:     time.Now(),
+		Percent:   b.PercentComplete(),
+		Thumbnail: b.thumbnail(),
+		Game:      sg,
+	}
+	if old := findSaveSlot(slots, name); old != nil {
+		*old = *ss
+		TheUI.Toast(fmt.Sprintf("Saved over '%s'", name))
+	} else {
+		slots = append(slots, ss)
+		TheUI.Toast(fmt.Sprintf("Saved as '%s'", name))
+	}
+	saveSaveSlots(slots)
+}
+
+func findSaveSlot(slots []*SaveSlot, name string) *SaveSlot {
+	for _, ss := range slots {
+		if ss.Name == name {
+			return ss
+		}
+	}
+	return nil
+}
+
+// LoadSlot replaces the current game with the one in the named slot, changing variant if need be.
+// A game in progress in another variant is stashed, as if the user had changed variant;
+// one in progress in this variant is replaced
+func (b *Baize) LoadSlot(name string) {
+	ss := findSaveSlot(loadSaveSlots(), name)
+	if ss == nil || ss.Game == nil {
+		TheUI.Toast(fmt.Sprintf("There is no saved game called '%s'", name))
+		return
+	}
+	if _, ok := Variants[ss.Variant]; !ok {
+		TheUI.Toast(fmt.Sprintf("Don't know how to play '%s'", ss.Variant))
+		return
+	}
+	if err := ss.Game.check(); err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not load '%s', because %s", name, err))
+		return
+	}
+	// the rest can only be checked against the variant's piles and cards, which means changing to it
+	prev := ThePreferences.Variant
+	if ss.Variant != prev {
+		b.ChangeVariant(ss.Variant)
+	}
+	if err := b.restoreGame(ss.Game); err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not load '%s', because %s", name, err))
+		if ThePreferences.Variant != prev {
+			b.ChangeVariant(prev)
+		}
+		return
+	}
+	TheUI.Toast(fmt.Sprintf("Loaded '%s'", name))
+}
+
+// DeleteSlot removes the named slot, and shows what's left
+func DeleteSlot(name string) {
+	slots := loadSaveSlots()
+	for i, ss := range slots {
+		if ss.Name == name {
+			slots = append(slots[:i], slots[i+1:]...)
+			saveSaveSlots(slots)
+			TheUI.Toast(fmt.Sprintf("Deleted '%s'", name))
+			break
+		}
+	}
+	ShowSlotsDrawer()
+}
+
+// ShowSlotsDrawer lists the saved games, newest first
+func ShowSlotsDrawer() {
+	slots := loadSaveSlots()
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Saved.After(slots[j].Saved) })
+	var infos []ui.SlotInfo
+	for _, ss := range slots {
+		si := ui.SlotInfo{
+			Name:    ss.Name,
+			Variant: ss.Variant,
+			Details: fmt.Sprintf("%s, %d%%", ss.Saved.Format("2 Jan 2006 15:04"), ss.Percent),
+		}
+		if ss.Name == slotDeletePending {
+			si.Details = "Tap the cross again to delete"
+			si.DeletePending = true
+		}
+		if len(ss.Thumbnail) > 0 {
+			if img, err := png.Decode(bytes.NewReader(ss.Thumbnail)); err == nil {
+				si.Thumbnail = img
+			}
+		}
+		infos = append(infos, si)
+	}
+	TheUI.ShowSlotsDrawer(slotName, newSlotName(slots), infos)
+}
+
+// slotsCommand handles the requests that keep the saved games drawer open
+func slotsCommand(request string, data string) {
+	switch request {
+	case "Slot name":
+		slotName = data
+	case "Delete slot":
+		// deleting can't be undone, so it takes two taps
+		slotDeletePending = data
+		ShowSlotsDrawer()
+	case "Delete slot confirmed":
+		slotDeletePending = ""
+		DeleteSlot(data)
+	default:
+		log.Println("unknown slots request", request, data)
+	}
+}
@@ -2,6 +2,7 @@ package sol
 
 import (
 	"fmt"
+	"time"
 
 	"oddstream.games/gosol/sound"
 	"oddstream.games/gosol/util"
@@ -10,7 +11,16 @@ import (
 // Statistics is a container for the statistics for all variants
 type Statistics struct {
 	// PascalCase for JSON
-	StatsMap map[string]*VariantStatistics
+	Version  int
+	Checksum string // see statsfile.go
+	// Unverified is set when the checksum didn't match, and stays set
+	Unverified bool `json:",omitempty"`
+	StatsMap   map[string]*VariantStatistics
+	// Device identifies this device, and Devices holds what has been imported from others (see statsync.go)
+	Device  string
+	Devices map[string]*DeviceStatistics `json:",omitempty"`
+	// Achievements holds when each achievement was unlocked (see achievements.go)
+	Achievements map[string]time.Time `json:",omitempty"`
 }
 
 // VariantStatistics holds the statistics for one variant
@@ -22,6 +32,18 @@ type VariantStatistics struct {
 	// Won + Lost is total number of games played (won or abandoned)
 	// SumPercents is a record of games where % < 100
 	// average % is (sum of Percents) + (100 * Won) / (Won+Lost)
+
+	// Timed is the number of won games with a time and move count (games won before there was a timer don't have them)
+	// times are in seconds
+	Timed, BestTime, SumTime, BestMoves, SumMoves int `json:",omitempty"`
+
+	// BestScores is the best score of a single game for each scoring model that has been used;
+	// Bankroll is the running total of the cumulative (Vegas) scores
+	BestScores map[string]int `json:",omitempty"`
+	Bankroll   int            `json:",omitempty"`
+
+	// Since is when the statistics were last reset; games in the history before then don't count
+	Since time.Time
 }
 
 func (stats *VariantStatistics) averagePercent() int {
@@ -32,6 +54,66 @@ func (stats *VariantStatistics) averagePercent() int {
 	return 0
 }
 
+func (stats *VariantStatistics) averageTime() time.Duration {
+	if stats.Timed > 0 {
+		return time.Duration(stats.SumTime/stats.Timed) * time.Second
+	}
+	return 0
+}
+
+func (stats *VariantStatistics) averageMoves() int {
+	if stats.Timed > 0 {
+		return stats.SumMoves / stats.Timed
+	}
+	return 0
+}
+
+// recordTimeAndMoves adds a won game's time and moves to the bests and averages
+func (stats *VariantStatistics) recordTimeAndMoves(elapsed time.Duration, moves int) []string {
+	var toasts []string
+	secs := int(elapsed / time.Second)
+	if stats.Timed == 0 || secs < stats.BestTime {
+		if stats.Timed > 0 {
+			toasts = append(toasts, fmt.Sprintf("That's your fastest game yet, beating %s", util.FormatDuration(time.Duration(stats.BestTime)*time.Second)))
+		}
+		stats.BestTime = secs
+	}
+	if stats.Timed == 0 || moves < stats.BestMoves {
+		if stats.Timed > 0 {
+			toasts = append(toasts, fmt.Sprintf("That's your fewest moves yet, beating %d", stats.BestMoves))
+		}
+		stats.BestMoves = moves
+	}
+	stats.Timed++
+	stats.SumTime += secs
+	stats.SumMoves += moves
+	return toasts
+}
+
+// recordScore remembers the score of a finished game, if the user is keeping score
+func (stats *VariantStatistics) recordScore() []string {
+	sm := TheBaize.scoringModel()
+	if _, ok := sm.(*NoScoring); ok {
+		return nil
+	}
+	var toasts []string
+	score := TheBaize.Score()
+	if best, ok := stats.BestScores[TheBaize.scoring]; !ok || score > best {
+		if ok {
+			toasts = append(toasts, fmt.Sprintf("That's your best score yet, beating %s", sm.Format(best)))
+		}
+		if stats.BestScores == nil {
+			stats.BestScores = make(map[string]int)
+		}
+		stats.BestScores[TheBaize.scoring] = score
+	}
+	if sm.Cumulative() {
+		stats.Bankroll += score
+		toasts = append(toasts, fmt.Sprintf("Your bankroll is now %s", sm.Format(stats.Bankroll)))
+	}
+	return toasts
+}
+
 func (stats *VariantStatistics) generalToasts() []string {
 
 	v := TheBaize.LongVariantName()
@@ -49,6 +131,15 @@ func (stats *VariantStatistics) generalToasts() []string {
 		toasts = append(toasts, fmt.Sprintf("Your average score is %d%%", avpc))
 	}
 
+	if stats.Timed > 0 {
+		toasts = append(toasts, fmt.Sprintf("Your best time is %s, your average time is %s", util.FormatDuration(time.Duration(stats.BestTime)*time.Second), util.FormatDuration(stats.averageTime())))
+		toasts = append(toasts, fmt.Sprintf("Your fewest moves is %d, your average is %d", stats.BestMoves, stats.averageMoves()))
+	}
+
+	if best, ok := stats.BestScores[TheBaize.scoring]; ok {
+		toasts = append(toasts, fmt.Sprintf("Your best %s score is %s", TheBaize.scoring, TheBaize.scoringModel().Format(best)))
+	}
+
 	if stats.CurrStreak > 1 {
 		toasts = append(toasts, fmt.Sprintf("You are on a winning streak of %s", util.Pluralize("game", stats.CurrStreak)))
 	}
@@ -62,7 +153,12 @@ func (stats *VariantStatistics) generalToasts() []string {
 // NewStatistics creates a new Statistics object
 func NewStatistics() *Statistics {
 	s := &Statistics{StatsMap: make(map[string]*VariantStatistics)}
-	s.Load()
+	if s.Load() || s.Device == "" {
+		if s.Device == "" {
+			s.Device = newDeviceID()
+		}
+		s.Save()
+	}
 	return s
 }
 
@@ -79,7 +175,15 @@ func (s *Statistics) findVariant(v string) *VariantStatistics {
 func (s *Statistics) RecordWonGame(v string) {
 
 	sound.Play("Complete")
-	TheUI.Toast(fmt.Sprintf("Recording completed game of %s", v))
+	if DemoMode {
+		return // nobody is playing, so there's nothing to record
+	}
+	appendGameRecord(TheBaize.gameRecord("won"))
+	if date, ok := dailyDate(TheBaize.script.Stock().Seed()); ok {
+		recordDailyResult(date, "won")
+		return
+	}
+	TheUI.Toast(fmt.Sprintf("Recording completed game of %s, in %s and %s", v, util.FormatDuration(TheBaize.elapsed), util.Pluralize("move", TheBaize.moves)))
 
 	stats := s.findVariant(v)
 
@@ -96,21 +200,31 @@ func (s *Statistics) RecordWonGame(v string) {
 
 	stats.BestPercent = 100
 
-	toasts := stats.generalToasts()
+	toasts := stats.recordTimeAndMoves(TheBaize.elapsed, TheBaize.moves)
+	toasts = append(toasts, stats.recordScore()...)
+	toasts = append(toasts, stats.generalToasts()...)
 	for _, t := range toasts {
 		TheUI.Toast(t)
 	}
 
+	s.unlockAchievements(TheBaize.achievementFacts())
+
 	s.Save()
 }
 
 func (s *Statistics) RecordLostGame(v string) {
 
-	percent := TheBaize.PercentComplete()
-	if percent == 100 {
-		println("*** That's odd, here is a lost game that is 100% complete ***")
+	if DemoMode {
+		return
 	}
 
+	percent := TheBaize.PercentComplete()
+
+	appendGameRecord(TheBaize.gameRecord("lost"))
+	if date, ok := dailyDate(TheBaize.script.Stock().Seed()); ok {
+		recordDailyResult(date, "lost")
+		return
+	}
 	TheUI.Toast(fmt.Sprintf("Recording lost game of %s, %d%% complete", v, percent))
 
 	stats := s.findVariant(v)
@@ -131,6 +245,10 @@ func (s *Statistics) RecordLostGame(v string) {
 	}
 	stats.SumPercents += percent
 
+	for _, t := range stats.recordScore() {
+		TheUI.Toast(t)
+	}
+
 	s.Save()
 }
 
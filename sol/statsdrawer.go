@@ -0,0 +1,225 @@
+package sol
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"oddstream.games/gosol/ui"
+	"oddstream.games/gosol/util"
+)
+
+// The statistics drawer shows a table of every variant that has been played,
+// which can be sorted by tapping a heading. Tapping a variant shows more about it,
+// with charts drawn from the history, and a way of starting it's statistics again
+
+var statsHeadings = []string{"Variant", "Played", "Won", "Win%", "Streak", "Best", "Best%", "Avg%", "Time"}
+var statsWidths = []int{168, 48, 40, 48, 48, 40, 48, 48, 64}
+
+var (
+	statsSortColumn     int
+	statsSortDescending bool
+	statsResetPending   string // variant that has had it's reset tapped once
+)
+
+// statsRecentGames is the number of games shown in the sparkline
+const statsRecentGames = 30
+
+func (stats *VariantStatistics) played() int {
+	return stats.Won + stats.Lost
+}
+
+func (stats *VariantStatistics) winPercent() int {
+	if played := stats.played(); played > 0 {
+		return stats.Won * 100 / played
+	}
+	return 0
+}
+
+// statsRow is one row of the table, with the numbers to sort by and the text to show
+type statsRow struct {
+	variant string
+	values  []int
+	cells   []string
+}
+
+func newStatsRow(v string, stats *VariantStatistics) statsRow {
+	bestTime := "-"
+	if stats.Timed > 0 {
+		bestTime = util.FormatDuration(time.Duration(stats.BestTime) * time.Second)
+	}
+	values := []int{0, stats.played(), stats.Won, stats.winPercent(), stats.CurrStreak, stats.BestStreak, stats.BestPercent, stats.averagePercent(), stats.BestTime}
+	cells := []string{v}
+	for _, n := range values[1 : len(values)-1] {
+		cells = append(cells, strconv.Itoa(n))
+	}
+	return statsRow{variant: v, values: values, cells: append(cells, bestTime)}
+}
+
+// statsRows makes a row for each variant that has been played, sorted the way the user asked
+func statsRows() []statsRow {
+	var rows []statsRow
+	for v, stats := range TheStatistics.StatsMap {
+		if stats.played() > 0 {
+			rows = append(rows, newStatsRow(v, stats))
+		}
+	}
+	less := func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if statsSortColumn == 0 || a.values[statsSortColumn] == b.values[statsSortColumn] {
+			return a.variant < b.variant
+		}
+		return a.values[statsSortColumn] < b.values[statsSortColumn]
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i].values[statsSortColumn], rows[j].values[statsSortColumn]
+		if statsSortColumn == len(statsHeadings)-1 && a != b && (a == 0 || b == 0) {
+			// variants that haven't been won don't have a best time, so put them last, whichever way it's sorted
+			return a != 0
+		}
+		if statsSortDescending {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+	return rows
+}
+
+// ShowStatisticsDrawer shows the table of statistics for every variant played
+func ShowStatisticsDrawer() {
+	statsResetPending = ""
+	rows := statsRows()
+	// Roboto doesn't have any arrows
+	headings := append([]string(nil), statsHeadings...)
+	if statsSortDescending {
+		headings[statsSortColumn] += " v"
+	} else {
+		headings[statsSortColumn] += " ^"
+	}
+	var cells [][]string
+	for _, row := range rows {
+		cells = append(cells, row.cells)
+	}
+	var entries []ui.ListEntry
+	if TheStatistics.Unverified {
+		entries = append(entries, ui.ListEntry{Text: "Edited outside the game", Detail: "These statistics may not be genuine"})
+	}
+	entries = append(entries, ui.ListEntry{Icon: "done", Text: "Export statistics", Detail: "To take to another device", RequestType: "Statistics export"})
+	if canPickFiles {
+		entries = append(entries, ui.ListEntry{Icon: "restore", Text: "Import statistics", Detail: "Exported from another device", RequestType: "Statistics import"})
+	}
+	TheUI.ShowStatisticsTable(headings, statsWidths, cells, entries)
+}
+
+// variantHistory returns the games of a variant in the history that count towards it's statistics, oldest first
+func variantHistory(v string, since time.Time) []*GameRecord {
+	var games []*GameRecord
+	for _, gr := range loadHistory() {
+		if gr.Variant == v && gr.Daily == "" && !gr.Ended.Before(since) {
+			games = append(games, gr)
+		}
+	}
+	return games
+}
+
+// ShowStatisticsDetail shows everything there is to know about one variant
+func ShowStatisticsDetail(v string) {
+	stats, ok := TheStatistics.StatsMap[v]
+	if !ok {
+		ShowStatisticsDrawer()
+		return
+	}
+
+	detail := ui.StatisticsDetail{Variant: v}
+	detail.Lines = append(detail.Lines, fmt.Sprintf("Played %s, won %d (%d%%), lost %d", util.Pluralize("time", stats.played()), stats.Won, stats.winPercent(), stats.Lost))
+	detail.Lines = append(detail.Lines, fmt.Sprintf("Current streak %d, best %d, worst %d", stats.CurrStreak, stats.BestStreak, stats.WorstStreak))
+	detail.Lines = append(detail.Lines, fmt.Sprintf("Best %d%%, average %d%%", stats.BestPercent, stats.averagePercent()))
+	if stats.Timed > 0 {
+		detail.Lines = append(detail.Lines, fmt.Sprintf("Best time %s, average %s", util.FormatDuration(time.Duration(stats.BestTime)*time.Second), util.FormatDuration(stats.averageTime())))
+		detail.Lines = append(detail.Lines, fmt.Sprintf("Fewest moves %d, average %d", stats.BestMoves, stats.averageMoves()))
+	}
+	for _, name := range ScoringModelNames {
+		if best, ok := stats.BestScores[name]; ok {
+			detail.Lines = append(detail.Lines, fmt.Sprintf("Best %s score %s", name, ScoringModels[name].Format(best)))
+		}
+	}
+	if !stats.Since.IsZero() {
+		detail.Lines = append(detail.Lines, "Since "+stats.Since.Format("2 Jan 2006"))
+	}
+
+	// games from before there was a history aren't in the charts
+	games := variantHistory(v, stats.Since)
+	detail.Histogram = make([]int, 11)
+	for _, gr := range games {
+		detail.Histogram[util.ClampInt(gr.Percent/10, 0, 10)]++
+	}
+	if len(games) > statsRecentGames {
+		games = games[len(games)-statsRecentGames:]
+	}
+	for _, gr := range games {
+		detail.Recent = append(detail.Recent, gr.Percent)
+	}
+
+	reset := ui.ListEntry{Icon: "close", Text: "Reset statistics", RequestType: "Statistics reset", Data: v}
+	if statsResetPending == v {
+		reset.Text = "Tap again to reset"
+		reset.Detail = "The history of games is kept"
+		reset.RequestType = "Statistics reset confirmed"
+	}
+	detail.Entries = []ui.ListEntry{reset, {Icon: "list", Text: "All variants", RequestType: "Statistics"}}
+	TheUI.ShowStatisticsDetail(detail)
+}
+
+// resetStatistics starts the statistics of a variant again
+func resetStatistics(v string) {
+	if _, ok := TheStatistics.StatsMap[v]; !ok {
+		return
+	}
+	TheStatistics.StatsMap[v] = &VariantStatistics{Since: time.Now()}
+	// otherwise a newer import from another device would take it's old games away again
+	for _, ds := range TheStatistics.Devices {
+		delete(ds.StatsMap, v)
+	}
+	TheStatistics.Save()
+	TheUI.Toast(fmt.Sprintf("Statistics for %s have been reset", v))
+}
+
+// statisticsCommand handles the change requests from the statistics drawer, which stays open
+func statisticsCommand(request string, data string) {
+	switch strings.TrimPrefix(request, "Statistics") {
+	case "":
+		ShowStatisticsDrawer()
+	case " sort":
+		column, err := strconv.Atoi(data)
+		if err != nil || column < 0 || column >= len(statsHeadings) {
+			log.Println("bad statistics column", data)
+			return
+		}
+		if column == statsSortColumn {
+			statsSortDescending = !statsSortDescending
+		} else {
+			// names and times read best smallest first, everything else biggest first
+			statsSortColumn, statsSortDescending = column, column != 0 && column != len(statsHeadings)-1
+		}
+		ShowStatisticsDrawer()
+	case " export":
+		exportStatisticsFile()
+	case " import":
+		pickStatisticsFile()
+	case " detail":
+		statsResetPending = ""
+		ShowStatisticsDetail(data)
+	case " reset":
+		statsResetPending = data
+		ShowStatisticsDetail(data)
+	case " reset confirmed":
+		statsResetPending = ""
+		resetStatistics(data)
+		ShowStatisticsDetail(data)
+	default:
+		log.Println("unknown statistics request", request, data)
+	}
+}
@@ -0,0 +1,36 @@
+package sol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatsRowsPutNoBestTimeLast(t *testing.T) {
+	defer func(stats *Statistics) { TheStatistics = stats }(TheStatistics)
+	TheStatistics = &Statistics{StatsMap: map[string]*VariantStatistics{
+		"Klondike": {Won: 1, Timed: 1, BestTime: 300},
+		"Freecell": {Won: 2, Timed: 2, BestTime: 200},
+		"Spider":   {Lost: 3},
+		"Yukon":    {Won: 1, Timed: 1, BestTime: 400},
+		"Scorpion": {Lost: 1},
+		"Unplayed": {},
+	}}
+	statsSortColumn = len(statsHeadings) - 1
+	for _, tc := range []struct {
+		descending bool
+		want       []string
+	}{
+		{false, []string{"Freecell", "Klondike", "Yukon", "Scorpion", "Spider"}},
+		{true, []string{"Yukon", "Klondike", "Freecell", "Spider", "Scorpion"}},
+	} {
+		statsSortDescending = tc.descending
+		var got []string
+		for _, row := range statsRows() {
+			got = append(got, row.variant)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("descending %v: got %v, wanted %v", tc.descending, got, tc.want)
+		}
+	}
+	statsSortColumn, statsSortDescending = 0, false
+}
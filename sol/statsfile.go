@@ -0,0 +1,205 @@
+package sol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"oddstream.games/gosol/util"
+)
+
+// statistics.json carries a version number, so old files can be brought up to date,
+// and a checksum, so a file that has been edited by hand can be spotted.
+// The checksum isn't a secret; it only catches people who don't know it's there.
+// Whatever is loaded is checked for numbers that can't be right, which are repaired
+
+const statisticsVersion = 1
+
+// statisticsMigrations bring Statistics of version n up to version n+1
+var statisticsMigrations = map[int]func(*Statistics){
+	// version 1 added the version and checksum, which needs nothing doing to the statistics
+}
+
+// renameVariants moves the statistics of variants that have been renamed since they were saved to their new name,
+// here and in what was imported from other devices, returning true if any were moved
+func (s *Statistics) renameVariants() bool {
+	renamed := renameVariants(s.StatsMap)
+	for _, ds := range s.Devices {
+		if ds != nil && renameVariants(ds.StatsMap) {
+			renamed = true
+		}
+	}
+	return renamed
+}
+
+func renameVariants(statsMap map[string]*VariantStatistics) bool {
+	var renamed bool
+	for v, stats := range statsMap {
+		cv := canonicalVariant(v)
+		if cv == v {
+			continue
+		}
+		delete(statsMap, v)
+		if existing, ok := statsMap[cv]; ok {
+			existing.mergeBests(stats)
+			existing.add(stats, 1)
+		} else {
+			statsMap[cv] = stats
+		}
+		renamed = true
+	}
+	return renamed
+}
+
+func (s *Statistics) migrate() {
+	for s.Version < statisticsVersion {
+		if fn, ok := statisticsMigrations[s.Version]; ok {
+			fn(s)
+		}
+		s.Version++
+	}
+}
+
+// checksum of everything but the checksum itself; encoding/json sorts map keys, so it's always the same
+func (s *Statistics) checksum() string {
+	cp := *s
+	cp.Checksum = ""
+	bytes, err := json.Marshal(cp)
+	if err != nil {
+		log.Println("Statistics checksum", err)
+		return ""
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// verify flags statistics that don't match their checksum; files from before there was one can't be checked
+func (s *Statistics) verify() {
+	if s.Checksum == "" || s.Checksum == s.checksum() {
+		return
+	}
+	log.Println("statistics checksum does not match")
+	s.Unverified = true
+}
+
+// recentResults returns the results ("won" or "lost") of the games of each variant in the history, oldest first
+func recentResults(s *Statistics) map[string][]string {
+	results := make(map[string][]string)
+	for _, gr := range loadHistory() {
+		if stats, ok := s.StatsMap[gr.Variant]; ok && gr.Daily == "" && !gr.Ended.Before(stats.Since) {
+			results[gr.Variant] = append(results[gr.Variant], gr.Result)
+		}
+	}
+	return results
+}
+
+// repair puts right anything in the statistics for a variant that can't be true,
+// returning a description of each thing it had to put right.
+// results are the games of the variant in the history, which say how the last game went
+func (stats *VariantStatistics) repair(v string, results []string) []string {
+	var repairs []string
+	fix := func(field *int, value int, why string) {
+		repairs = append(repairs, fmt.Sprintf("%s: %s %d changed to %d", v, why, *field, value))
+		*field = value
+	}
+
+	for _, f := range []struct {
+		field *int
+		name  string
+	}{
+		{&stats.Won, "Won"}, {&stats.Lost, "Lost"}, {&stats.SumPercents, "SumPercents"},
+		{&stats.BestStreak, "BestStreak"}, {&stats.BestPercent, "BestPercent"},
+		{&stats.Timed, "Timed"}, {&stats.BestTime, "BestTime"}, {&stats.SumTime, "SumTime"},
+		{&stats.BestMoves, "BestMoves"}, {&stats.SumMoves, "SumMoves"},
+	} {
+		if *f.field < 0 {
+			fix(f.field, 0, "negative "+f.name)
+		}
+	}
+
+	if stats.BestPercent > 100 {
+		fix(&stats.BestPercent, 100, "BestPercent")
+	}
+	if stats.Won > 0 && stats.BestPercent < 100 {
+		fix(&stats.BestPercent, 100, "BestPercent of a won variant")
+	}
+	// a lost game is at most 99%
+	if stats.SumPercents > 99*stats.Lost {
+		fix(&stats.SumPercents, 99*stats.Lost, "SumPercents")
+	}
+
+	if len(results) > 0 {
+		last := results[len(results)-1]
+		run := 0
+		for i := len(results) - 1; i >= 0 && results[i] == last; i-- {
+			run++
+		}
+		if last == "won" && stats.CurrStreak <= 0 {
+			fix(&stats.CurrStreak, util.Min(run, stats.Won), "CurrStreak after a won game")
+		}
+		if last == "lost" && stats.CurrStreak >= 0 {
+			fix(&stats.CurrStreak, -util.Min(run, stats.Lost), "CurrStreak after a lost game")
+		}
+	}
+	if stats.CurrStreak > stats.Won {
+		fix(&stats.CurrStreak, stats.Won, "CurrStreak longer than games won")
+	}
+	if stats.CurrStreak < -stats.Lost {
+		fix(&stats.CurrStreak, -stats.Lost, "CurrStreak longer than games lost")
+	}
+	if stats.BestStreak > stats.Won {
+		fix(&stats.BestStreak, stats.Won, "BestStreak longer than games won")
+	}
+	if stats.BestStreak < stats.CurrStreak {
+		fix(&stats.BestStreak, stats.CurrStreak, "BestStreak shorter than CurrStreak")
+	}
+	if stats.WorstStreak > 0 {
+		fix(&stats.WorstStreak, 0, "positive WorstStreak")
+	}
+	if stats.WorstStreak < -stats.Lost {
+		fix(&stats.WorstStreak, -stats.Lost, "WorstStreak longer than games lost")
+	}
+	if stats.WorstStreak > stats.CurrStreak {
+		fix(&stats.WorstStreak, stats.CurrStreak, "WorstStreak shorter than CurrStreak")
+	}
+
+	if stats.Timed > stats.Won {
+		fix(&stats.Timed, stats.Won, "Timed more than games won")
+	}
+	if stats.Timed == 0 {
+		for _, field := range []*int{&stats.BestTime, &stats.SumTime, &stats.BestMoves, &stats.SumMoves} {
+			if *field != 0 {
+				fix(field, 0, "time or moves without a timed game")
+			}
+		}
+	} else {
+		if stats.BestTime*stats.Timed > stats.SumTime {
+			fix(&stats.BestTime, stats.SumTime/stats.Timed, "BestTime worse than average")
+		}
+		if stats.BestMoves*stats.Timed > stats.SumMoves {
+			fix(&stats.BestMoves, stats.SumMoves/stats.Timed, "BestMoves worse than average")
+		}
+	}
+
+	return repairs
+}
+
+// repair checks the statistics of every variant, returning true if anything had to be put right
+func (s *Statistics) repair() bool {
+	results := recentResults(s)
+	var repaired bool
+	for v, stats := range s.StatsMap {
+		if stats == nil {
+			delete(s.StatsMap, v)
+			repaired = true
+			continue
+		}
+		for _, r := range stats.repair(v, results[v]) {
+			log.Println("repaired statistics", r)
+			repaired = true
+		}
+	}
+	return repaired
+}
@@ -0,0 +1,249 @@
+package sol
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"oddstream.games/gosol/util"
+)
+
+// Statistics can be exported from one device and imported into another.
+// Every device has it's own id, and StatsMap holds the totals of the games played on this device
+// plus the ones imported from each of the others. The contribution of each other device is remembered,
+// so importing a newer export from the same device replaces the old contribution rather than adding to it,
+// and importing the same file twice doesn't count anything twice.
+// An export carries the contributions of every device it knows about, so they can be passed along.
+
+const statisticsExportKind = "gosol statistics"
+
+// DeviceStatistics is what another device had played, as of when it was exported
+type DeviceStatistics struct {
+	Exported time.Time
+	StatsMap map[string]*VariantStatistics
+}
+
+// StatisticsExport is the portable file made by exportStatistics
+type StatisticsExport struct {
+	Kind     string
+	Version  int
+	Device   string
+	Exported time.Time
+	StatsMap map[string]*VariantStatistics // games played on the exporting device
+	Devices  map[string]*DeviceStatistics  `json:",omitempty"`
+	// Achievements unlocked on any device the exporting one knows about
+	Achievements map[string]time.Time `json:",omitempty"`
+}
+
+// renamedVariants maps the old name of a variant to it's new one,
+// so statistics exported by an older version go to the right place,
+// as do the ones saved here under the old name (see renameVariants)
+var renamedVariants = map[string]string{}
+
+// importedStatistics is how a browser hands over an export the user has picked,
+// which happens outside of the game loop
+var importedStatistics = make(chan []byte, 1)
+
+func newDeviceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// not random, but different enough from other devices
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// canonicalVariant returns the name a variant is known by here; a relaxed variant keeps it's suffix
+func canonicalVariant(v string) string {
+	base := strings.TrimSuffix(v, " Relaxed")
+	if renamed, ok := renamedVariants[base]; ok {
+		return renamed + strings.TrimPrefix(v, base)
+	}
+	return v
+}
+
+// knownVariant is true if this device knows how to play the variant
+func knownVariant(v string) bool {
+	_, ok := Variants[strings.TrimSuffix(v, " Relaxed")]
+	return ok
+}
+
+// add adds (or with sign -1, takes away) the counts of another device's games.
+// Nothing is allowed to go below zero, in case the statistics were reset in between
+func (stats *VariantStatistics) add(o *VariantStatistics, sign int) {
+	sum := func(a *int, b int) {
+		*a += sign * b
+		if *a < 0 {
+			*a = 0
+		}
+	}
+	sum(&stats.Won, o.Won)
+	sum(&stats.Lost, o.Lost)
+	sum(&stats.SumPercents, o.SumPercents)
+	sum(&stats.Timed, o.Timed)
+	sum(&stats.SumTime, o.SumTime)
+	sum(&stats.SumMoves, o.SumMoves)
+	stats.Bankroll += sign * o.Bankroll
+}
+
+// mergeBests keeps the better of each best. Bests can't be taken away again, but that doesn't matter,
+// because merging the same ones twice doesn't change anything. The current streak is left alone;
+// it's about the games played here, one after another
+func (stats *VariantStatistics) mergeBests(o *VariantStatistics) {
+	stats.BestStreak = util.Max(stats.BestStreak, o.BestStreak)
+	if o.WorstStreak < stats.WorstStreak {
+		stats.WorstStreak = o.WorstStreak
+	}
+	stats.BestPercent = util.Max(stats.BestPercent, o.BestPercent)
+	if o.Timed > 0 {
+		if stats.Timed == 0 || o.BestTime < stats.BestTime {
+			stats.BestTime = o.BestTime
+		}
+		if stats.Timed == 0 || o.BestMoves < stats.BestMoves {
+			stats.BestMoves = o.BestMoves
+		}
+	}
+	for name, score := range o.BestScores {
+		if best, ok := stats.BestScores[name]; !ok || score > best {
+			if stats.BestScores == nil {
+				stats.BestScores = make(map[string]int)
+			}
+			stats.BestScores[name] = score
+		}
+	}
+}
+
+// ownStatistics works out what was played on this device, by taking away what was imported
+func (s *Statistics) ownStatistics() map[string]*VariantStatistics {
+	own := make(map[string]*VariantStatistics)
+	for v, stats := range s.StatsMap {
+		cp := *stats
+		for _, ds := range s.Devices {
+			if o, ok := ds.StatsMap[v]; ok {
+				cp.add(o, -1)
+			}
+		}
+		own[v] = &cp
+	}
+	return own
+}
+
+// exportStatistics makes a portable copy of the statistics
+func (s *Statistics) exportStatistics() ([]byte, error) {
+	se := StatisticsExport{
+		Kind:     statisticsExportKind,
+		Version:  1,
+		Device:   s.Device,
+		Exported: time.Now(),
+		StatsMap: s.ownStatistics(),
+		Devices:  s.Devices,
+
+		Achievements: s.Achievements,
+	}
+	return json.MarshalIndent(se, "", "\t")
+}
+
+// mergeDevice replaces what is known about the games played on another device
+func (s *Statistics) mergeDevice(device string, ds *DeviceStatistics) {
+	if old, ok := s.Devices[device]; ok {
+		for v, o := range old.StatsMap {
+			if stats, ok := s.StatsMap[v]; ok {
+				stats.add(o, -1)
+			}
+		}
+	}
+	renamed := make(map[string]*VariantStatistics)
+	for v, o := range ds.StatsMap {
+		v = canonicalVariant(v)
+		if o == nil {
+			continue
+		}
+		if prev, ok := renamed[v]; ok {
+			// both the old and the new name were played on the other device
+			prev.mergeBests(o)
+			prev.add(o, 1)
+			continue
+		}
+		cp := *o
+		cp.repair(v, nil)
+		renamed[v] = &cp
+	}
+	for v, o := range renamed {
+		stats := s.findVariant(v)
+		stats.mergeBests(o)
+		stats.add(o, 1)
+	}
+	if s.Devices == nil {
+		s.Devices = make(map[string]*DeviceStatistics)
+	}
+	s.Devices[device] = &DeviceStatistics{Exported: ds.Exported, StatsMap: renamed}
+}
+
+// importStatistics merges an export from another device, returning the variants that were merged
+// that aren't played here
+func (s *Statistics) importStatistics(bytes []byte) (merged int, unknown []string, err error) {
+	var se StatisticsExport
+	if err = json.Unmarshal(bytes, &se); err != nil {
+		return 0, nil, err
+	}
+	if se.Kind != statisticsExportKind || se.Device == "" {
+		return 0, nil, errors.New("not a statistics export")
+	}
+	if se.Device == s.Device {
+		return 0, nil, errors.New("it was exported from this device")
+	}
+	s.mergeAchievements(se.Achievements)
+	snapshots := map[string]*DeviceStatistics{se.Device: {Exported: se.Exported, StatsMap: se.StatsMap}}
+	for device, ds := range se.Devices {
+		if device != se.Device {
+			snapshots[device] = ds
+		}
+	}
+	seen := make(map[string]bool)
+	for device, ds := range snapshots {
+		if device == s.Device || ds == nil {
+			continue // this device knows better about it's own games
+		}
+		if old, ok := s.Devices[device]; ok && !ds.Exported.After(old.Exported) {
+			continue // already got this, or something newer
+		}
+		s.mergeDevice(device, ds)
+		merged++
+		for v := range s.Devices[device].StatsMap {
+			if !knownVariant(v) && !seen[v] {
+				seen[v] = true
+				unknown = append(unknown, v)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return merged, unknown, nil
+}
+
+// mergeStatistics imports an export and tells the user how it went
+func (s *Statistics) mergeStatistics(bytes []byte) {
+	merged, unknown, err := s.importStatistics(bytes)
+	if err != nil {
+		TheUI.Toast(fmt.Sprintf("Could not import statistics, because %s", err))
+		return
+	}
+	// the other device's games may be enough to unlock something here
+	s.unlockAchievements(nil)
+	s.Save()
+	if merged == 0 {
+		TheUI.Toast("Those statistics have already been imported")
+		return
+	}
+	TheUI.Toast(fmt.Sprintf("Imported statistics from %s", util.Pluralize("device", merged)))
+	if len(unknown) > 0 {
+		// kept anyway, so they aren't lost if they are exported again
+		TheUI.Toast(fmt.Sprintf("Kept statistics for %s not played here: %s", util.Pluralize("variant", len(unknown)), strings.Join(unknown, ", ")))
+	}
+	log.Println("imported statistics", merged, unknown)
+}
@@ -0,0 +1,106 @@
+package sol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestImportStatisticsDoesNotDoubleCount(t *testing.T) {
+	renamedVariants["Old Freecell"] = "Freecell"
+	defer delete(renamedVariants, "Old Freecell")
+
+	here := &Statistics{Device: "here", StatsMap: map[string]*VariantStatistics{"Freecell": {Won: 1, BestStreak: 1}}}
+	export := func(exported time.Time, won int) []byte {
+		bytes, _ := json.Marshal(StatisticsExport{
+			Kind:     statisticsExportKind,
+			Device:   "there",
+			Exported: exported,
+			StatsMap: map[string]*VariantStatistics{"Old Freecell": {Won: won, BestStreak: 3}, "Made Up": {Lost: 1}},
+		})
+		return bytes
+	}
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, _, err := here.importStatistics(export(now, 2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a best streak of 3 out of 2 games won can't be right, so it's repaired to 2
+	if fc := here.StatsMap["Freecell"]; fc.Won != 3 || fc.BestStreak != 2 {
+		t.Errorf("wrong totals after importing twice: %+v", fc)
+	}
+	if fc := here.Devices["there"].StatsMap["Freecell"]; fc.BestStreak != 2 {
+		t.Errorf("imported BestStreak should have been repaired to games won, not %d", fc.BestStreak)
+	}
+	if _, ok := here.StatsMap["Made Up"]; !ok {
+		t.Error("unknown variant should have been kept")
+	}
+
+	// a newer export from the same device replaces the old one
+	if merged, _, _ := here.importStatistics(export(now.Add(time.Hour), 5)); merged != 1 {
+		t.Errorf("newer export should have been merged")
+	}
+	if won := here.StatsMap["Freecell"].Won; won != 6 {
+		t.Errorf("wrong total after newer import: %d", won)
+	}
+	if own := here.ownStatistics()["Freecell"]; own.Won != 1 {
+		t.Errorf("wrong count of games played here: %d", own.Won)
+	}
+
+	// passed back, this device's own games are left alone
+	bytes, _ := here.exportStatistics()
+	there := &Statistics{Device: "there", StatsMap: map[string]*VariantStatistics{}}
+	if _, _, err := there.importStatistics(bytes); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := there.Devices["there"]; ok {
+		t.Error("should not have imported it's own games")
+	}
+}
+
+func TestImportStatisticsRenamedVariants(t *testing.T) {
+	renamedVariants["Old Freecell"] = "Freecell"
+	defer delete(renamedVariants, "Old Freecell")
+
+	for _, tc := range []struct {
+		name   string
+		here   map[string]*VariantStatistics // as saved by an older version
+		there  map[string]*VariantStatistics
+		won    map[string]int
+		absent string
+	}{
+		{"old name in the export", map[string]*VariantStatistics{"Freecell": {Won: 1}},
+			map[string]*VariantStatistics{"Old Freecell": {Won: 2}}, map[string]int{"Freecell": 3}, "Old Freecell"},
+		{"old and new names in the export", map[string]*VariantStatistics{"Freecell": {Won: 1}},
+			map[string]*VariantStatistics{"Old Freecell": {Won: 2}, "Freecell": {Won: 4}}, map[string]int{"Freecell": 7}, "Old Freecell"},
+		{"old name here", map[string]*VariantStatistics{"Old Freecell": {Won: 1}},
+			map[string]*VariantStatistics{"Freecell": {Won: 2}}, map[string]int{"Freecell": 3}, "Old Freecell"},
+		{"old and new names here", map[string]*VariantStatistics{"Old Freecell": {Won: 1}, "Freecell": {Won: 2}},
+			map[string]*VariantStatistics{"Freecell": {Won: 4}}, map[string]int{"Freecell": 7}, "Old Freecell"},
+		{"relaxed", map[string]*VariantStatistics{"Old Freecell Relaxed": {Won: 1}},
+			map[string]*VariantStatistics{"Old Freecell Relaxed": {Won: 2}}, map[string]int{"Freecell Relaxed": 3}, "Old Freecell Relaxed"},
+	} {
+		TheStorage = NewMemoryStorage()
+		(&Statistics{Device: "here", StatsMap: tc.here}).Save()
+		here := NewStatistics()
+
+		// import it twice, the second time newer, which should replace the first, not add to it
+		now := time.Now()
+		for _, exported := range []time.Time{now, now.Add(time.Hour)} {
+			bytes, _ := json.Marshal(StatisticsExport{Kind: statisticsExportKind, Device: "there", Exported: exported, StatsMap: tc.there})
+			if _, _, err := here.importStatistics(bytes); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+		}
+		for v, won := range tc.won {
+			if stats, ok := here.StatsMap[v]; !ok || stats.Won != won {
+				t.Errorf("%s: %s should have %d won, got %+v", tc.name, v, won, stats)
+			}
+		}
+		if _, ok := here.StatsMap[tc.absent]; ok {
+			t.Errorf("%s: %s should have been renamed", tc.name, tc.absent)
+		}
+	}
+}
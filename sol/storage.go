@@ -0,0 +1,165 @@
+package sol
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Storage is somewhere to keep the preferences, statistics and saved games.
+// Things are stored by name (eg "preferences.json") as blobs of bytes;
+// turning them into something useful is up to the caller
+type Storage interface {
+	// Load returns the named blob, or nil (and no error) if there isn't one
+	Load(name string) ([]byte, error)
+	// Save replaces the named blob, all at once; it's never left half written
+	Save(name string, bytes []byte) error
+	// Append adds to the end of the named blob, creating it if need be
+	Append(name string, bytes []byte) error
+	// Remove deletes the named blob, if there is one
+	Remove(name string) error
+	// Where describes where a blob is kept, for telling the user
+	Where(name string) string
+	// Sub returns a Storage whose blobs are kept apart from these, like a subdirectory
+	Sub(name string) Storage
+}
+
+// TheStorage is where everything is loaded from and saved to.
+// It's set by NewGame (see defaultStorage) unless something else got there first,
+// and is changed to the profile's own storage when there's more than one player (see profiles.go)
+var TheStorage Storage
+
+// sharedStorage is where things that belong to everyone who plays on this device are kept,
+// like the custom variants; it's the same as TheStorage until a profile is chosen
+var sharedStorage Storage
+
+func theSharedStorage() Storage {
+	if sharedStorage == nil {
+		return TheStorage
+	}
+	return sharedStorage
+}
+
+// DataDir is the directory to keep files in, set by command line flag -datadir.
+// If empty, the user's config directory is used
+var DataDir string = ""
+
+// MemoryStorage keeps everything in a map, and forgets it all when the app exits.
+// Good for tests, and for when nothing should be saved
+type MemoryStorage struct {
+	blobs  map[string][]byte
+	prefix string // of the names of the blobs that belong to a Sub storage
+}
+
+// NewMemoryStorage creates an empty MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{blobs: make(map[string][]byte)}
+}
+
+func (ms *MemoryStorage) Load(name string) ([]byte, error) {
+	bytes, ok := ms.blobs[ms.prefix+name]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte(nil), bytes...), nil
+}
+
+func (ms *MemoryStorage) Save(name string, bytes []byte) error {
+	ms.blobs[ms.prefix+name] = append([]byte(nil), bytes...)
+	return nil
+}
+
+func (ms *MemoryStorage) Append(name string, bytes []byte) error {
+	ms.blobs[ms.prefix+name] = append(ms.blobs[ms.prefix+name], bytes...)
+	return nil
+}
+
+func (ms *MemoryStorage) Remove(name string) error {
+	delete(ms.blobs, ms.prefix+name)
+	return nil
+}
+
+func (ms *MemoryStorage) Where(name string) string {
+	return "memory:" + ms.prefix + name
+}
+
+// Sub shares the same map, so everything can be seen from the top
+func (ms *MemoryStorage) Sub(name string) Storage {
+	return &MemoryStorage{blobs: ms.blobs, prefix: ms.prefix + name + "/"}
+}
+
+// Names returns the names of everything stored, in order
+func (ms *MemoryStorage) Names() []string {
+	var names []string
+	for name := range ms.blobs {
+		if strings.HasPrefix(name, ms.prefix) {
+			names = append(names, strings.TrimPrefix(name, ms.prefix))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// quarantine moves a blob that can't be loaded out of the way (rather than deleting it,
+// so it can be looked at later) so it doesn't stop the game from starting next time
+func quarantine(st Storage, name string, bytes []byte) {
+	corrupt := fmt.Sprintf("%s.corrupt-%s", name, time.Now().Format("20060102-150405"))
+	if err := st.Save(corrupt, bytes); err != nil {
+		log.Println(err)
+	}
+	if err := st.Remove(name); err != nil {
+		log.Println(err)
+	}
+	log.Println("moved", st.Where(name), "to", corrupt)
+}
+
+// loadData hands the named blob to decode.
+// If it can't be read or decoded, it's quarantined and the backup is tried instead.
+// Returns false if there was nothing to load.
+func loadData(name string, decode func([]byte) error) bool {
+	return loadDataFrom(TheStorage, name, decode)
+}
+
+// loadDataFrom is loadData from somewhere other than TheStorage
+func loadDataFrom(st Storage, name string, decode func([]byte) error) bool {
+	for _, n := range []string{name, name + ".bak"} {
+		bytes, err := st.Load(n)
+		if err == nil && len(bytes) == 0 {
+			continue
+		}
+		if err == nil {
+			err = decode(bytes)
+		}
+		if err == nil {
+			return true
+		}
+		log.Println("cannot load", st.Where(n), err)
+		if bytes != nil {
+			quarantine(st, n, bytes)
+		}
+	}
+	return false
+}
+
+// saveData replaces the named blob, keeping the old one as a backup in case the new one turns out to be bad.
+// Errors are logged, because there's nothing else to be done about them,
+// and it's better to keep playing than to crash
+func saveData(name string, bytes []byte) error {
+	return saveDataTo(TheStorage, name, bytes)
+}
+
+// saveDataTo is saveData to somewhere other than TheStorage
+func saveDataTo(st Storage, name string, bytes []byte) error {
+	if old, err := st.Load(name); err == nil && len(old) > 0 {
+		if err := st.Save(name+".bak", old); err != nil {
+			log.Println("cannot back up", st.Where(name), err)
+		}
+	}
+	err := st.Save(name, bytes)
+	if err != nil {
+		log.Println("cannot save", st.Where(name), err)
+	}
+	return err
+}
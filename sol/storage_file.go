@@ -0,0 +1,122 @@
+//go:build !js
+
+package sol
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileStorage keeps each blob in a file of the same name in a directory
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage creates a FileStorage; the directory is created when something is first saved
+func NewFileStorage(dir string) *FileStorage {
+	return &FileStorage{dir: dir}
+}
+
+// defaultStorage is the -datadir directory, or oddstream.games/gosol in the user's config directory
+func defaultStorage() Storage {
+	if DataDir != "" {
+		return NewFileStorage(DataDir)
+	}
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		println(err.Error(), "so nothing will be saved")
+		return NewMemoryStorage()
+	}
+	// println("UserConfigDir", userConfigDir) // /home/gilbert/.config
+	return NewFileStorage(filepath.Join(userConfigDir, "oddstream.games", "gosol"))
+}
+
+func (st *FileStorage) path(name string) string {
+	return filepath.Join(st.dir, name)
+}
+
+func (st *FileStorage) Where(name string) string {
+	return st.path(name)
+}
+
+func (st *FileStorage) Sub(name string) Storage {
+	return NewFileStorage(filepath.Join(st.dir, name))
+}
+
+func (st *FileStorage) Load(name string) ([]byte, error) {
+	bytes, err := os.ReadFile(st.path(name))
+	if isNotExist(err) {
+		return nil, nil // file does not exist (which is ok)
+	}
+	if err == nil && DebugMode {
+		println("loaded", st.path(name))
+	}
+	return bytes, err
+}
+
+// Save puts the new contents in a temporary file which is then renamed over the old one,
+// so a crash or power cut can't leave the file half written
+func (st *FileStorage) Save(name string, bytes []byte) error {
+	// if path is already a directory, MkdirAll does nothing and returns nil
+	if err := os.MkdirAll(st.dir, 0755); err != nil { // https://stackoverflow.com/questions/14249467/os-mkdir-and-os-mkdirall-permission-value
+		return err
+	}
+	path := st.path(name)
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(bytes); err != nil {
+		file.Close()
+		return err
+	}
+	// make sure it's on the disk before the rename, or a power cut could leave an empty file
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if DebugMode {
+		println("saved", path)
+	}
+	return nil
+}
+
+// Append writes to the end of the file, and makes sure it's on the disk
+func (st *FileStorage) Append(name string, bytes []byte) error {
+	if err := os.MkdirAll(st.dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(st.path(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(bytes); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (st *FileStorage) Remove(name string) error {
+	if err := os.Remove(st.path(name)); err != nil && !isNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func isNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,208 @@
+package sol
+
+import (
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestSaveDataKeepsBackup(t *testing.T) {
+	ms := NewMemoryStorage()
+	TheStorage = ms
+
+	saveData("test.json", []byte("one"))
+	saveData("test.json", []byte("two"))
+
+	if bytes, _ := ms.Load("test.json"); string(bytes) != "two" {
+		t.Errorf("wrong contents after save: %s", bytes)
+	}
+	if bytes, _ := ms.Load("test.json.bak"); string(bytes) != "one" {
+		t.Errorf("wrong backup after save: %s", bytes)
+	}
+}
+
+func TestLoadDataQuarantinesCorruptData(t *testing.T) {
+	ms := NewMemoryStorage()
+	TheStorage = ms
+	ms.Save("test.json", []byte(`{"Variant": "Freec`))
+	ms.Save("test.json.bak", []byte(`{"Variant": "Freecell"}`))
+
+	var prefs Preferences
+	if !loadData("test.json", func(bytes []byte) error { return json.Unmarshal(bytes, &prefs) }) {
+		t.Fatal("should have loaded the backup")
+	}
+	if prefs.Variant != "Freecell" {
+		t.Errorf("wrong variant loaded from backup: %s", prefs.Variant)
+	}
+	if bytes, _ := ms.Load("test.json"); bytes != nil {
+		t.Error("corrupt data should have been moved out of the way")
+	}
+	var quarantined bool
+	for _, name := range ms.Names() {
+		if strings.HasPrefix(name, "test.json.corrupt-") {
+			quarantined = true
+		}
+	}
+	if !quarantined {
+		t.Errorf("corrupt data should have been kept, got %v", ms.Names())
+	}
+
+	if loadData("missing.json", func([]byte) error { return nil }) {
+		t.Error("should be nothing to load")
+	}
+}
+
+func TestPreferencesSaveLoad(t *testing.T) {
+	TheStorage = NewMemoryStorage()
+
+	saved := &Preferences{Variant: "Spider One Suit", FixedCardWidth: 90}
+	saved.Save()
+
+	loaded := &Preferences{}
+	loaded.Load()
+	if loaded.Variant != saved.Variant || loaded.FixedCardWidth != saved.FixedCardWidth {
+		t.Errorf("preferences did not survive a round trip: %+v", loaded)
+	}
+}
+
+func TestReplayJournal(t *testing.T) {
+	TheStorage = NewMemoryStorage()
+
+	state := func(bookmark int) *SavableBaize { return &SavableBaize{Bookmark: bookmark} }
+	snapshot := []*SavedGame{{Version: savedGameVersion, Variant: "Freecell", UndoStack: []*SavableBaize{state(1), state(2)}}}
+
+	var journal []byte
+	for _, je := range []journalEntry{
+		{Seq: 2, Variant: "Freecell", Len: 1, States: []*SavableBaize{state(2)}}, // already in the snapshot
+		{Seq: 3, Variant: "Freecell", Len: 2, States: []*SavableBaize{state(3)}},
+		{Seq: 4, Variant: "Freecell", Len: 2, States: []*SavableBaize{state(4)}}, // an undo
+		{Seq: 5, Variant: "Spider", Len: 0, States: []*SavableBaize{state(1), state(2)}},
+	} {
+		bytes, _ := json.Marshal(je)
+		journal = append(journal, append(bytes, '\n')...)
+	}
+	journal = append(journal, []byte(`{"Seq": 6, "Vari`)...) // killed halfway through writing
+
+	games, seq := replayJournal(snapshot, 2, journal)
+	if seq != 5 {
+		t.Errorf("wrong last sequence number: %d", seq)
+	}
+	if len(games) != 2 {
+		t.Fatalf("wrong number of games: %d", len(games))
+	}
+	for _, sg := range games {
+		switch sg.Variant {
+		case "Freecell":
+			if len(sg.UndoStack) != 3 || sg.UndoStack[2].Bookmark != 4 {
+				t.Errorf("Freecell replayed incorrectly, %d states", len(sg.UndoStack))
+			}
+		case "Spider":
+			if len(sg.UndoStack) != 2 {
+				t.Errorf("Spider replayed incorrectly, %d states", len(sg.UndoStack))
+			}
+		}
+	}
+}
+
+func TestSaveWithoutLoadingKeepsSavedGames(t *testing.T) {
+	TheStorage = NewMemoryStorage()
+	NoGameLoad = true
+	defer func() { NoGameLoad = false }()
+
+	state := func(bookmark int) *SavableBaize { return &SavableBaize{Bookmark: bookmark} }
+	bytes, _ := json.Marshal(&savedGamesFile{Seq: 1, Games: []*SavedGame{
+		{Version: savedGameVersion, Variant: "Freecell", UndoStack: []*SavableBaize{state(1), state(2)}},
+		{Version: savedGameVersion, Variant: "Spider", UndoStack: []*SavableBaize{state(1), state(2)}},
+	}})
+	saveData("saved.json", bytes)
+	je, _ := json.Marshal(journalEntry{Seq: 2, Variant: "Freecell", Len: 2, States: []*SavableBaize{state(3)}})
+	appendToJournal(append(je, '\n'))
+
+	// started with -noload, so the saved games were never given to the baize
+	b := &Baize{}
+	b.Save()
+
+	games, seq := LoadSavedGames()
+	if len(games) != 2 {
+		t.Errorf("saved games were written over, %d left", len(games))
+	}
+	if seq != 2 {
+		t.Error("journal should have been left alone")
+	}
+}
+
+func TestUnmarshalOldSavedGames(t *testing.T) {
+	ThePreferences.Variant = "Klondike"
+
+	// version 0 was just an undo stack
+	games, _, err := unmarshalSavedGames([]byte(`[{"Bookmark": 1}, {"Bookmark": 2}]`))
+	if err != nil || len(games) != 1 || games[0].Variant != "Klondike" || games[0].Version != savedGameVersion {
+		t.Errorf("version 0 saved game not migrated: %v", err)
+	}
+
+	// version 1 was a map of variant name to undo stack
+	games, _, err = unmarshalSavedGames([]byte(`{"Freecell": [{"Bookmark": 1}, {"Bookmark": 2}]}`))
+	if err != nil || len(games) != 1 || games[0].Variant != "Freecell" {
+		t.Errorf("version 1 saved game not migrated: %v", err)
+	}
+
+	games, seq, err := unmarshalSavedGames([]byte(`{"Seq": 7, "Games": [{"Version": 2, "Variant": "Yukon", "UndoStack": [{}, {}]}]}`))
+	if err != nil || seq != 7 || len(games) != 1 || games[0].Variant != "Yukon" {
+		t.Errorf("current saved game not loaded: %v", err)
+	}
+}
+
+func TestStatisticsLoadRepairsAndVerifies(t *testing.T) {
+	ms := NewMemoryStorage()
+	TheStorage = ms
+	appendGameRecord(&GameRecord{Variant: "Freecell", Result: "lost"})
+
+	// version 0 had no checksum, so can't be verified, but is still repaired
+	ms.Save("statistics.json", []byte(`{"StatsMap": {"Freecell": {"Won": 2, "Lost": 1, "CurrStreak": 2, "BestStreak": 2, "BestPercent": 140}}}`))
+	s := NewStatistics()
+	if s.Version != statisticsVersion || s.Unverified {
+		t.Errorf("version 0 statistics not migrated: %+v", s)
+	}
+	if fc := s.StatsMap["Freecell"]; fc.CurrStreak != -1 || fc.BestPercent != 100 {
+		t.Errorf("statistics not repaired: %+v", fc)
+	}
+
+	// changed by hand after being saved with a checksum
+	bytes, _ := ms.Load("statistics.json")
+	ms.Save("statistics.json", []byte(strings.Replace(string(bytes), `"Won": 2`, `"Won": 20`, 1)))
+	if s = NewStatistics(); !s.Unverified {
+		t.Error("edited statistics should have been flagged")
+	}
+	if s = NewStatistics(); !s.Unverified {
+		t.Error("edited statistics should stay flagged")
+	}
+}
+
+func TestCheckSavedGameChecksCards(t *testing.T) {
+	TheBaize = &Baize{}
+	ThePreferences.Variant = "Freecell"
+	CreateCardLibrary(1, 1, nil, 0)
+	NewCell(image.Point{})
+
+	game := func(cards ...CardID) *SavedGame {
+		sp := &SavablePile{Category: "Cell", Cards: cards}
+		return &SavedGame{Version: savedGameVersion, Variant: "Freecell", UndoStack: []*SavableBaize{{Piles: []*SavablePile{sp}}}}
+	}
+	var spades []CardID
+	for i := range CardLibrary {
+		spades = append(spades, CardLibrary[i].ID)
+	}
+	if err := TheBaize.checkSavedGame(game(spades...)); err != nil {
+		t.Errorf("good saved game rejected: %v", err)
+	}
+
+	hearts := append([]CardID{NewCardID(0, HEART, 1)}, spades[1:]...)
+	if err := TheBaize.checkSavedGame(game(hearts...)); err == nil {
+		t.Error("saved game with a card not in the variant should be rejected")
+	}
+	twice := append([]CardID{spades[1]}, spades[1:]...)
+	if err := TheBaize.checkSavedGame(game(twice...)); err == nil {
+		t.Error("saved game with the same card twice should be rejected")
+	}
+}
@@ -0,0 +1,70 @@
+// https://github.com/golang/go/wiki/WebAssembly
+// https://pkg.go.dev/syscall/js
+
+package sol
+
+import (
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+const keyPrefix = "gosol/"
+
+// LocalStorage keeps each blob as a string in the browser's localStorage
+type LocalStorage struct {
+	prefix string
+}
+
+// defaultStorage is the browser's localStorage; there's no such thing as a directory
+func defaultStorage() Storage {
+	return &LocalStorage{prefix: keyPrefix}
+}
+
+// key is the name without the .json, which is how things were always stored in localStorage
+func (ls *LocalStorage) key(name string) string {
+	return ls.prefix + strings.TrimSuffix(name, ".json")
+}
+
+func (ls *LocalStorage) Where(name string) string {
+	return "localStorage " + ls.key(name)
+}
+
+// Sub keeps it's blobs under a longer prefix
+func (ls *LocalStorage) Sub(name string) Storage {
+	return &LocalStorage{prefix: ls.prefix + name + "/"}
+}
+
+func localStorage() js.Value {
+	return js.Global().Get("window").Get("localStorage")
+}
+
+func (ls *LocalStorage) Load(name string) ([]byte, error) {
+	v := localStorage().Call("getItem", ls.key(name))
+	if v.IsNull() || v.IsUndefined() {
+		return nil, nil
+	}
+	return []byte(v.String()), nil
+}
+
+// Save sets the value all at once. localStorage throws if it's full (or disabled), which would otherwise panic
+func (ls *LocalStorage) Save(name string, bytes []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	localStorage().Call("setItem", ls.key(name), string(bytes))
+	return nil
+}
+
+// Append has to rewrite the whole value, as localStorage can only store whole strings
+func (ls *LocalStorage) Append(name string, bytes []byte) error {
+	old, _ := ls.Load(name)
+	return ls.Save(name, append(old, bytes...))
+}
+
+func (ls *LocalStorage) Remove(name string) error {
+	localStorage().Call("removeItem", ls.key(name))
+	return nil
+}
@@ -22,6 +22,14 @@ func (b *Baize) FindHomeForTail(owner Pile, tail []*Card) Pile {
 				return dst
 			}
 		}
+		for _, dst := range b.script.Grid() {
+			if dst == owner {
+				continue
+			}
+			if ok, _ := dst.CanAcceptTail(tail); ok {
+				return dst
+			}
+		}
 	}
 	for _, dst := range b.script.Tableaux() {
 		if dst == owner {
@@ -38,6 +46,10 @@ func meaninglessMove(dst Pile, src Pile, tail []*Card) bool {
 	if _, isFoundation := (dst).(*Foundation); isFoundation {
 		return false
 	}
+	if _, isGridCell := (src).(*GridCell); isGridCell {
+		// shuffling a card that's already in place from one gap to another
+		return src.Complete()
+	}
 	if dst.Empty() {
 		if len(tail) == src.Len() {
 			return true
@@ -67,6 +79,9 @@ func (b *Baize) Stuck() bool {
 	if !b.script.Stock().Empty() {
 		moves++
 	}
+	if b.redeals > 0 {
+		moves++
+	}
 	if wastePile := b.script.Waste(); wastePile != nil {
 		if !wastePile.Empty() {
 			var tail []*Card
@@ -102,6 +117,20 @@ func (b *Baize) Stuck() bool {
 			}
 		}
 	}
+	for _, pile := range b.script.Grid() {
+		if pile.Empty() {
+			continue
+		}
+		tail := pile.MakeTail(pile.Peek())
+		if dst := b.FindHomeForTail(pile, tail); dst != nil {
+			if !meaninglessMove(dst, pile, tail) {
+				if DebugMode {
+					tail[0].movable = true
+				}
+				moves++
+			}
+		}
+	}
 	for _, pile := range b.script.Reserves() {
 		for _, card := range pile.cards {
 			if card.Prone() {
@@ -0,0 +1,88 @@
+package sol
+
+import (
+	"log"
+	"strconv"
+
+	"oddstream.games/gosol/ui"
+)
+
+// The timeline shows the current line of play (the undo stack) along the bottom of the screen.
+// Dragging along it previews earlier positions without changing the undo stack;
+// letting go goes to the position, keeping the moves after it as a branch, like going to a bookmark
+
+// timelineMarkers finds the bookmarks, moves to a foundation and stock recycles in the undo stack
+func timelineMarkers(undoStack []*SavableBaize) []ui.TimelineMarker {
+	var markers []ui.TimelineMarker
+	if len(undoStack) == 0 {
+		return markers
+	}
+	foundationCards := func(sb *SavableBaize) int {
+		var n int
+		for _, sp := range sb.Piles {
+			if sp.Category == "Foundation" {
+				n += len(sp.Cards)
+			}
+		}
+		return n
+	}
+	prev := undoStack[0]
+	for i, sb := range undoStack[1:] {
+		if foundationCards(sb) > foundationCards(prev) {
+			markers = append(markers, ui.TimelineMarker{Position: i + 1, Kind: "foundation"})
+		}
+		if sb.Recycles < prev.Recycles {
+			markers = append(markers, ui.TimelineMarker{Position: i + 1, Kind: "recycle"})
+		}
+		prev = sb
+	}
+	for _, bm := range undoStack[len(undoStack)-1].Bookmarks {
+		markers = append(markers, ui.TimelineMarker{Position: bm.Len - 1, Kind: "bookmark"})
+	}
+	return markers
+}
+
+// ToggleTimeline shows or hides the timeline
+func (b *Baize) ToggleTimeline() {
+	if TheUI.ToggleTimeline() {
+		b.updateTimeline()
+	}
+}
+
+func (b *Baize) updateTimeline() {
+	if TheUI.TimelineVisible() {
+		TheUI.SetTimeline(len(b.undoStack), timelineMarkers(b.undoStack))
+	}
+}
+
+// previewPosition shows the cards as they were at a position on the undo stack, without going there
+func (b *Baize) previewPosition(position int) {
+	if position < 0 || position >= len(b.undoStack) {
+		return
+	}
+	b.previewing = position != len(b.undoStack)-1
+	sb := b.undoStack[position]
+	for i := 0; i < len(sb.Piles); i++ {
+		b.piles[i].UpdateFromSavable(sb.Piles[i])
+	}
+	b.setFlag(dirtyCardPositions)
+}
+
+// timelineCommand handles the change requests sent by the timeline
+func timelineCommand(cmd string, data string) {
+	position, err := strconv.Atoi(data)
+	if err != nil {
+		log.Println("bad timeline position", data, err)
+		return
+	}
+	switch cmd {
+	case "Timeline preview":
+		TheBaize.previewPosition(position)
+	case "Timeline goto":
+		// put the cards back first, so GotoPosition sees the game as it really is
+		TheBaize.previewPosition(len(TheBaize.undoStack) - 1)
+		if position < len(TheBaize.undoStack)-1 {
+			TheBaize.GotoPosition(-1, position+1)
+		}
+	}
+}
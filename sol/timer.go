@@ -0,0 +1,44 @@
+package sol
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/util"
+)
+
+// Each game keeps count of the moves made (an undo isn't a move, and doesn't take one away)
+// and how long it has been played for. The clock doesn't start until the first move,
+// stops when the game is complete, and doesn't run while the window is in the background
+// or a drawer is open. Both are saved with the game, see SavedGame
+
+// tickTimer is called every tick, to run the clock
+func (b *Baize) tickTimer() {
+	if len(b.undoStack) < 2 || !ebiten.IsFocused() || TheUI.VisibleDrawer() != nil || b.Complete() {
+		return
+	}
+	before := b.elapsed / time.Second
+	b.elapsed += time.Second / time.Duration(ebiten.MaxTPS())
+	if b.elapsed/time.Second != before {
+		b.updateMiddleStatus()
+	}
+}
+
+// countMove is called after every move the user (or autoplay) makes
+func (b *Baize) countMove() {
+	if b.moves == 0 {
+		b.started = time.Now()
+	}
+	b.moves++
+	b.updateMiddleStatus()
+}
+
+// updateMiddleStatus shows the moves, time and score (which may depend on the time) in the statusbar
+func (b *Baize) updateMiddleStatus() {
+	status := fmt.Sprintf("MOVES: %d  TIME: %s", b.moves, util.FormatDuration(b.elapsed))
+	if score := b.scoreStatus(); score != "" {
+		status += "  " + score
+	}
+	TheUI.SetMiddle(status)
+}
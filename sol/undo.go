@@ -18,9 +18,11 @@ type SavablePile struct {
 }
 
 type SavableBaize struct {
-	Piles    []*SavablePile `json:",omitempty"`
-	Bookmark int            `json:",omitempty"`
-	Recycles int            `json:",omitempty"`
+	Piles     []*SavablePile `json:",omitempty"`
+	Bookmark  int            `json:",omitempty"` // only found in old saves, see savedGameMigrations
+	Bookmarks []Bookmark     `json:",omitempty"`
+	Recycles  int            `json:",omitempty"`
+	Redeals   int            `json:",omitempty"`
 }
 
 func (self *Core) Savable() *SavablePile {
@@ -31,24 +33,37 @@ func (self *Core) Savable() *SavablePile {
 	return sp
 }
 
+// libraryIndex finds the card in CardLibrary that a saved card id stands for, or returns -1
+func libraryIndex(cid CardID) int {
+	for i := 0; i < len(CardLibrary); i++ {
+		if SameCardAndPack(cid, CardLibrary[i].ID) || SameJoker(cid, CardLibrary[i].ID) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (self *Core) UpdateFromSavable(sp *SavablePile) {
 	if self.category != sp.Category {
 		log.Panic("Baize pile and SavablePile are different")
 	}
 	self.Reset()
 	for _, cid := range sp.Cards {
-		for i := 0; i < len(CardLibrary); i++ {
-			if SameCardAndPack(cid, CardLibrary[i].ID) {
-				c := &CardLibrary[i]
-				self.Push(c)
-				// Push() may have flipped the card, so do this afterwards ...
-				if cid.Prone() {
-					c.FlipDown()
-				} else {
-					c.FlipUp()
-				}
-				break
-			}
+		i := libraryIndex(cid)
+		if i < 0 {
+			continue
+		}
+		c := &CardLibrary[i]
+		if c.Joker() {
+			// restore whatever the joker was pretending to be
+			c.ID = c.ID&(proneFlag) | cid&^proneFlag
+		}
+		self.Push(c)
+		// Push() may have flipped the card, so do this afterwards ...
+		if cid.Prone() {
+			c.FlipDown()
+		} else {
+			c.FlipUp()
 		}
 	}
 	if len(self.cards) != len(sp.Cards) {
@@ -59,7 +74,7 @@ func (self *Core) UpdateFromSavable(sp *SavablePile) {
 }
 
 func (b *Baize) NewSavableBaize() *SavableBaize {
-	ss := &SavableBaize{Bookmark: b.bookmark, Recycles: b.recycles}
+	ss := &SavableBaize{Bookmarks: b.bookmarks, Recycles: b.recycles, Redeals: b.redeals}
 	for _, p := range b.piles {
 		ss.Piles = append(ss.Piles, p.Savable())
 	}
@@ -69,6 +84,8 @@ func (b *Baize) NewSavableBaize() *SavableBaize {
 func (b *Baize) UndoPush() {
 	ss := b.NewSavableBaize()
 	b.undoStack = append(b.undoStack, ss)
+	b.unsaved = true
+	b.journal(len(b.undoStack)-1, []*SavableBaize{ss})
 	b.UpdateStatusbar()
 }
 
@@ -81,6 +98,7 @@ func (b *Baize) UndoPeek() *SavableBaize {
 
 func (b *Baize) UndoPop() (*SavableBaize, bool) {
 	if len(b.undoStack) > 0 {
+		rebaseBranches(b.branches, b.undoStack, len(b.undoStack)-1)
 		sav := b.undoStack[len(b.undoStack)-1]
 		b.undoStack = b.undoStack[:len(b.undoStack)-1]
 		return sav, true
@@ -101,8 +119,10 @@ func (b *Baize) UpdateFromSavable(sb *SavableBaize) {
 	for i := 0; i < len(sb.Piles); i++ {
 		b.piles[i].UpdateFromSavable(sb.Piles[i])
 	}
-	b.bookmark = sb.Bookmark
+	b.bookmarks = sb.Bookmarks
 	b.recycles = sb.Recycles
+	b.redeals = sb.Redeals
+	b.autoDone = false // in case a self playing game has been undone
 	b.setFlag(dirtyCardPositions)
 }
 
@@ -117,6 +137,7 @@ func (b *Baize) Undo() {
 		TheUI.Toast("Cannot undo a completed game") // otherwise the stats can be cooked
 		return
 	}
+	b.undos++
 	_, ok := b.UndoPop() // removes current state
 	if !ok {
 		log.Panic("error popping current state from undo stack")
@@ -130,50 +151,10 @@ func (b *Baize) Undo() {
 	b.UndoPush() // replace current state
 }
 
+// RestartDeal goes back to the start of the deal; the moves made so far are kept as a branch
 func (b *Baize) RestartDeal() {
-	var sav *SavableBaize
-	var ok bool
-	for len(b.undoStack) > 0 {
-		sav, ok = b.UndoPop()
-		if !ok {
-			log.Panic("error popping from undo stack")
-		}
-	}
-	b.UpdateFromSavable(sav)
-	b.bookmark = 0 // do this AFTER UpdateFromSavable
-	b.UndoPush()   // replace current state
-}
-
-// SavePosition saves the current Baize state
-func (b *Baize) SavePosition() {
-	if b.Complete() {
-		TheUI.Toast("Cannot bookmark a completed game") // otherwise the stats can be cooked
-		sound.Play("Blip")
-		return
-	}
-	b.bookmark = len(b.undoStack)
-	sb := b.UndoPeek()
-	sb.Bookmark = b.bookmark
-	sb.Recycles = b.recycles
-	TheUI.Toast("Position bookmarked")
-}
-
-// LoadPosition loads a previously saved Baize state
-func (b *Baize) LoadPosition() {
-	if b.bookmark == 0 || b.bookmark > len(b.undoStack) || b.Complete() {
-		// println("bookmark", b.bookmark, "undostack", len(b.undoStack))
-		TheUI.Toast("No bookmark")
-		sound.Play("Blip")
+	if len(b.undoStack) == 0 {
 		return
 	}
-	var sav *SavableBaize
-	var ok bool
-	for len(b.undoStack)+1 > b.bookmark {
-		sav, ok = b.UndoPop()
-		if !ok {
-			log.Panic("error popping from undo stack")
-		}
-	}
-	b.UpdateFromSavable(sav)
-	b.UndoPush() // replace current state
+	b.switchLine(len(b.undoStack), nil, 1)
 }
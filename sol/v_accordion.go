@@ -0,0 +1,135 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"image"
+
+	"oddstream.games/gosol/sound"
+)
+
+type Accordion struct {
+	ScriptBase
+}
+
+func (*Accordion) Info() *VariantInfo {
+	return &VariantInfo{
+		windowShape: "landscape",
+		wikipedia:   "https://en.wikipedia.org/wiki/Accordion_(card_game)",
+		relaxable:   false,
+	}
+}
+
+func (ac *Accordion) BuildPiles() {
+
+	ac.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+
+	// the cards are dealt in a line, which wraps round into four rows of thirteen
+	ac.tableaux = nil
+	for i := 0; i < 52; i++ {
+		t := NewTableau(image.Point{i % 13, i / 13}, FAN_NONE, MOVE_ONE)
+		ac.tableaux = append(ac.tableaux, t)
+	}
+	// the game is won when all the cards have been gathered into the first pile
+	ac.tableaux[0].SetCompleteFunc(func(p Pile) bool { return p.Len() == len(CardLibrary) })
+}
+
+func (ac *Accordion) StartGame() {
+	for _, t := range ac.tableaux {
+		MoveCard(ac.stock, t)
+	}
+}
+
+// AfterMove closes up the gap left by a pile that has moved
+func (ac *Accordion) AfterMove() {
+	var i int
+	for _, t := range ac.tableaux {
+		if t.Empty() {
+			continue
+		}
+		if t != ac.tableaux[i] {
+			MoveAllCards(t, ac.tableaux[i])
+		}
+		i++
+	}
+}
+
+// index returns the position of a pile in the line, or -1
+func (ac *Accordion) index(pile Pile) int {
+	for i, t := range ac.tableaux {
+		if t == pile {
+			return i
+		}
+	}
+	return -1
+}
+
+// target returns the pile that the pile at index i can be moved onto, trying three to the left first, or nil
+func (ac *Accordion) target(i int) *Tableau {
+	if ac.tableaux[i].Empty() {
+		return nil
+	}
+	for _, n := range []int{3, 1} {
+		if i-n < 0 {
+			continue
+		}
+		pair := CardPair{ac.tableaux[i-n].Peek(), ac.tableaux[i].Peek()}
+		if ok, _ := pair.Compare_SuitOrRank(); ok {
+			return ac.tableaux[i-n]
+		}
+	}
+	return nil
+}
+
+// AutoMove plays the leftmost pile that can move, which is no strategy at all, but looks nice enough in DemoMode
+func (ac *Accordion) AutoMove() bool {
+	for i := range ac.tableaux {
+		if dst := ac.target(i); dst != nil {
+			MoveCards(ac.tableaux[i], 0, dst)
+			return true
+		}
+	}
+	return false
+}
+
+func (*Accordion) TailMoveError(tail []*Card) (bool, error) {
+	if tail[0].Owner().Len() > 1 {
+		return false, errors.New("Tap a pile to move all of it")
+	}
+	return true, nil
+}
+
+func (ac *Accordion) TailAppendError(dst Pile, tail []*Card) (bool, error) {
+	switch (dst).(type) {
+	case *Tableau:
+		if dst.Empty() {
+			return false, errors.New("Cannot move cards there")
+		}
+		if n := ac.index(tail[0].Owner()) - ac.index(dst); n != 1 && n != 3 {
+			return false, errors.New("A pile can only move onto the next one to the left, or the one three to the left")
+		}
+		return CardPair{dst.Peek(), tail[0]}.Compare_SuitOrRank()
+	}
+	return true, nil
+}
+
+func (ac *Accordion) UnsortedPairs(pile Pile) int {
+	// only cards that have been gathered into the first pile count as sorted,
+	// so the percent complete goes up as that pile grows
+	if pile == ac.tableaux[0] || pile.Empty() {
+		return 0
+	}
+	return pile.Len() - 1
+}
+
+func (ac *Accordion) TailTapped(tail []*Card) {
+	var src Pile = tail[0].Owner()
+	if dst := ac.target(ac.index(src)); dst != nil {
+		MoveCards(src, 0, dst)
+	} else {
+		sound.Play("Blip")
+	}
+}
+
+func (*Accordion) PileTapped(Pile) {}
@@ -0,0 +1,129 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"image"
+)
+
+type BeleagueredCastle struct {
+	ScriptBase
+	wikipedia      string
+	rows           int  // number of tableau rows each side of the foundations
+	dealAces       bool // Aces go to the foundations before the deal
+	dealToFounds   bool // cards are played to the foundations as they are dealt
+	moveType       MoveType
+	tabCompareFunc func(CardPair) (bool, error)
+}
+
+func (bc *BeleagueredCastle) Info() *VariantInfo {
+	return &VariantInfo{
+		windowShape: "square",
+		wikipedia:   bc.wikipedia,
+		relaxable:   false,
+	}
+}
+
+func (bc *BeleagueredCastle) BuildPiles() {
+
+	if bc.rows == 0 {
+		bc.rows = 4
+	}
+	if bc.moveType == MOVE_NONE /* 0 */ {
+		bc.moveType = MOVE_ONE_PLUS
+	}
+	if bc.tabCompareFunc == nil {
+		bc.tabCompareFunc = CardPair.Compare_Down
+	}
+
+	bc.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+
+	bc.foundations = nil
+	for y := 0; y < 4; y++ {
+		f := NewFoundation(image.Point{3, y})
+		bc.foundations = append(bc.foundations, f)
+		f.SetLabel("A")
+	}
+
+	// the tableaux fan out sideways, away from the central column of foundations,
+	// so the left-hand rows grow towards the left edge of the baize
+	bc.tableaux = nil
+	for y := 0; y < bc.rows; y++ {
+		t := NewTableau(image.Point{2, y}, FAN_LEFT, bc.moveType)
+		bc.tableaux = append(bc.tableaux, t)
+	}
+	for y := 0; y < bc.rows; y++ {
+		t := NewTableau(image.Point{4, y}, FAN_RIGHT, bc.moveType)
+		bc.tableaux = append(bc.tableaux, t)
+	}
+}
+
+func (bc *BeleagueredCastle) StartGame() {
+	if bc.dealAces {
+		MoveNamedCard(bc.stock, CLUB, 1, bc.foundations[0])
+		MoveNamedCard(bc.stock, DIAMOND, 1, bc.foundations[1])
+		MoveNamedCard(bc.stock, HEART, 1, bc.foundations[2])
+		MoveNamedCard(bc.stock, SPADE, 1, bc.foundations[3])
+	}
+	// deal in rounds, so the left-hand rows get any spare cards
+	for !bc.stock.Empty() {
+		for _, t := range bc.tableaux {
+			if MoveCard(bc.stock, t) == nil {
+				break
+			}
+			if bc.dealToFounds {
+				// the card has been flipped up by being dealt, so can now be checked
+				for _, f := range bc.foundations {
+					if ok, _ := f.CanAcceptCard(t.Peek()); ok {
+						MoveCard(t, f)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func (*BeleagueredCastle) AfterMove() {
+}
+
+func (bc *BeleagueredCastle) TailMoveError(tail []*Card) (bool, error) {
+	var pile Pile = tail[0].Owner()
+	switch (pile).(type) {
+	case *Tableau:
+		for _, pair := range NewCardPairs(tail) {
+			if ok, err := bc.tabCompareFunc(pair); !ok {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func (bc *BeleagueredCastle) TailAppendError(dst Pile, tail []*Card) (bool, error) {
+	switch (dst).(type) {
+	case *Foundation:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		} else {
+			return CardPair{dst.Peek(), tail[0]}.Compare_UpSuit()
+		}
+	case *Tableau:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		} else {
+			return bc.tabCompareFunc(CardPair{dst.Peek(), tail[0]})
+		}
+	}
+	return true, nil
+}
+
+func (bc *BeleagueredCastle) UnsortedPairs(pile Pile) int {
+	return UnsortedPairs(pile, bc.tabCompareFunc)
+}
+
+func (*BeleagueredCastle) TailTapped(tail []*Card) {
+	tail[0].Owner().TailTapped(tail)
+}
+
+func (*BeleagueredCastle) PileTapped(Pile) {}
@@ -0,0 +1,155 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"image"
+
+	"oddstream.games/gosol/util"
+)
+
+type Calculation struct {
+	ScriptBase
+	betsyRoss  bool          // eight foundations, the top row only showing the step of the one below it, and no waste heaps
+	builders   []*Foundation // the foundations that get built on, in order of step (1, 2, 3, 4)
+	indicators []*Foundation // Betsy Ross: each holds a single card showing the step of the foundation below it
+}
+
+func (cl *Calculation) Info() *VariantInfo {
+	var wikipedia string = "https://en.wikipedia.org/wiki/Calculation_(card_game)"
+	if cl.betsyRoss {
+		wikipedia = "https://en.wikipedia.org/wiki/Betsy_Ross_(solitaire)"
+	}
+	return &VariantInfo{
+		windowShape: "square",
+		wikipedia:   wikipedia,
+		relaxable:   false,
+	}
+}
+
+func (cl *Calculation) BuildPiles() {
+
+	cl.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	cl.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+
+	cl.foundations = nil
+	cl.builders = nil
+	cl.indicators = nil
+	cl.tableaux = nil
+	if cl.betsyRoss {
+		for x := 3; x < 7; x++ {
+			f := NewFoundation(image.Point{x, 0})
+			f.SetCapacity(1)
+			cl.foundations = append(cl.foundations, f)
+			cl.indicators = append(cl.indicators, f)
+		}
+		// 12 cards, including the first one (2, 4, 6 or 8), which is already there
+		for x := 3; x < 7; x++ {
+			f := NewFoundation(image.Point{x, 1})
+			f.SetCapacity(12)
+			cl.foundations = append(cl.foundations, f)
+			cl.builders = append(cl.builders, f)
+		}
+	} else {
+		for x := 3; x < 7; x++ {
+			f := NewFoundation(image.Point{x, 0})
+			cl.foundations = append(cl.foundations, f)
+			cl.builders = append(cl.builders, f)
+		}
+		// the waste heaps
+		for x := 3; x < 7; x++ {
+			cl.tableaux = append(cl.tableaux, NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ONE))
+		}
+	}
+}
+
+func (cl *Calculation) StartGame() {
+	// the starting cards can be of any suit, so take whichever one comes first in the shuffled stock
+	for i, f := range cl.indicators {
+		MoveNamedCard(cl.stock, NOSUIT, i+1, f)
+	}
+	for i, f := range cl.builders {
+		var ord int = i + 1
+		if cl.betsyRoss {
+			ord = (i + 1) * 2
+		}
+		MoveNamedCard(cl.stock, NOSUIT, ord, f)
+		f.SetLabel(util.OrdinalToShortString(ord))
+	}
+	if cl.betsyRoss {
+		TheBaize.SetRecycles(2)
+	} else {
+		TheBaize.SetRecycles(0)
+	}
+	MoveCard(cl.stock, cl.waste)
+}
+
+func (cl *Calculation) AfterMove() {
+	if cl.waste.Empty() && !cl.stock.Empty() {
+		MoveCard(cl.stock, cl.waste)
+	}
+}
+
+func (*Calculation) TailMoveError(tail []*Card) (bool, error) {
+	// attempt to move more than one card will be caught before this
+	return true, nil
+}
+
+// step returns the amount that each card on this foundation goes up by,
+// or zero if it isn't one that gets built on
+func (cl *Calculation) step(pile Pile) int {
+	for i, f := range cl.builders {
+		if f == pile {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (cl *Calculation) TailAppendError(dst Pile, tail []*Card) (bool, error) {
+	switch (dst).(type) {
+	case *Foundation:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		}
+		step := cl.step(dst)
+		if step == 0 {
+			return false, errors.New("That Foundation only shows the step of the one below it")
+		}
+		return CardPair{dst.Peek(), tail[0]}.Compare_UpStep(step)
+	case *Tableau:
+		// cards can leave a waste heap, but only to go to a foundation
+		if _, ok := (tail[0].Owner()).(*Waste); !ok {
+			return false, errors.New("Waste heaps can only accept cards from the Waste")
+		}
+	}
+	return true, nil
+}
+
+func (*Calculation) UnsortedPairs(pile Pile) int {
+	// there's no order to the cards in a waste heap
+	if pile.Empty() {
+		return 0
+	}
+	return pile.Len() - 1
+}
+
+func (cl *Calculation) TailTapped(tail []*Card) {
+	var pile Pile = tail[0].Owner()
+	if pile == cl.stock && len(tail) == 1 {
+		if !cl.betsyRoss && !cl.waste.Empty() {
+			TheUI.Toast("The Waste card must be played before turning another")
+			return
+		}
+		MoveCard(cl.stock, cl.waste)
+	} else {
+		pile.TailTapped(tail)
+	}
+}
+
+func (cl *Calculation) PileTapped(pile Pile) {
+	if pile == cl.stock && cl.betsyRoss {
+		RecycleWasteToStock(cl.waste, cl.stock)
+	}
+}
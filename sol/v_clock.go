@@ -0,0 +1,106 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"image"
+
+	"oddstream.games/gosol/sound"
+)
+
+type Clock struct {
+	ScriptBase
+	hours []*Tableau // one pile for each ordinal; Ace at one o'clock, Queen at twelve, Kings in the middle
+}
+
+func (*Clock) Info() *VariantInfo {
+	return &VariantInfo{
+		windowShape: "square",
+		wikipedia:   "https://en.wikipedia.org/wiki/Clock_(card_game)",
+		relaxable:   false,
+		autoPlay:    true,
+	}
+}
+
+func (cl *Clock) BuildPiles() {
+
+	cl.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+
+	// clock face laid out on a 7x5 grid of slots, starting at one o'clock
+	var slots = []image.Point{
+		{4, 0}, {5, 1}, {6, 2}, {5, 3}, {4, 4}, {3, 4}, // 1 .. 6
+		{2, 4}, {1, 3}, {0, 2}, {1, 1}, {2, 0}, {3, 0}, // 7 .. 12
+		{3, 2}, // Kings
+	}
+	cl.tableaux = nil
+	cl.hours = nil
+	for _, slot := range slots {
+		t := NewTableau(slot, FAN_NONE, MOVE_NONE)
+		// a pile is done when all it's cards have been turned up
+		t.SetCompleteFunc(func(p Pile) bool { return !AnyCardsProne(p.Cards()) })
+		cl.tableaux = append(cl.tableaux, t)
+		cl.hours = append(cl.hours, t)
+	}
+}
+
+func (cl *Clock) StartGame() {
+	for _, t := range cl.hours {
+		for i := 0; i < 4; i++ {
+			MoveCard(cl.stock, t).FlipDown()
+		}
+	}
+	if cl.stock.Len() > 0 {
+		println("*** still", cl.stock.Len(), "cards in Stock")
+	}
+}
+
+func (*Clock) AfterMove() {
+}
+
+// current returns the pile to turn the next card from; that's the one that has
+// just been given a card (so has five), or the Kings pile at the start and after a King is turned up
+func (cl *Clock) current() Pile {
+	for _, t := range cl.hours {
+		if t.Len() > 4 {
+			return t
+		}
+	}
+	return cl.hours[12]
+}
+
+// AutoMove turns up the top face down card of the current pile,
+// and puts it face up on the pile for it's ordinal
+func (cl *Clock) AutoMove() bool {
+	src := cl.current()
+	for i := src.Len() - 1; i >= 0; i-- {
+		c := src.Get(i)
+		if !c.Prone() {
+			continue
+		}
+		src.Delete(i)
+		c.FlipUp()
+		cl.hours[c.Ordinal()-1].Push(c)
+		sound.Play("Place")
+		return true
+	}
+	// the fourth King has turned up before the rest of the cards
+	return false
+}
+
+func (*Clock) TailMoveError(tail []*Card) (bool, error) {
+	return false, errors.New("Clock plays itself")
+}
+
+func (*Clock) TailAppendError(dst Pile, tail []*Card) (bool, error) {
+	return false, errors.New("Clock plays itself")
+}
+
+func (*Clock) UnsortedPairs(pile Pile) int {
+	// a pair is sorted once both cards have been turned up
+	return UnsortedPairs(pile, func(CardPair) (bool, error) { return true, nil })
+}
+
+func (*Clock) TailTapped([]*Card) {}
+
+func (*Clock) PileTapped(Pile) {}
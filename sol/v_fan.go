@@ -0,0 +1,125 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"image"
+)
+
+type Fan struct {
+	ScriptBase
+	wikipedia      string
+	redeals        int
+	dealAces       bool   // Aces go to the foundations before the deal (Trefoil)
+	buryKings      bool   // Kings are moved to the bottom of their fans (Shamrocks)
+	emptyLabel     string // "K" if an empty fan can be filled by a King, "x" if it can't be filled
+	maxTabLen      int    // the most cards a fan can hold, or zero if there is no limit
+	tabCompareFunc func(CardPair) (bool, error)
+}
+
+func (fn *Fan) Info() *VariantInfo {
+	return &VariantInfo{
+		windowShape: "square",
+		wikipedia:   fn.wikipedia,
+		relaxable:   fn.emptyLabel == "K",
+	}
+}
+
+func (fn *Fan) BuildPiles() {
+
+	if fn.tabCompareFunc == nil {
+		fn.tabCompareFunc = CardPair.Compare_DownSuit
+	}
+
+	if fn.redeals > 0 {
+		// the stock is only there to be tapped for a redeal
+		fn.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	} else {
+		fn.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	}
+
+	fn.foundations = nil
+	for x := 5; x < 9; x++ {
+		f := NewFoundation(image.Point{x, 0})
+		fn.foundations = append(fn.foundations, f)
+		f.SetLabel("A")
+	}
+
+	// 52 cards in fans of three is 17 fans and a spare card,
+	// or 48 cards in 16 fans if the Aces have already gone
+	var fans int = 18
+	if fn.dealAces {
+		fans = 16
+	}
+	fn.tableaux = nil
+	for i := 0; i < fans; i++ {
+		t := NewTableau(image.Point{(i % 5) * 2, 1 + i/5}, FAN_RIGHT, MOVE_ONE)
+		fn.tableaux = append(fn.tableaux, t)
+		t.SetLabel(fn.emptyLabel)
+	}
+}
+
+func (fn *Fan) StartGame() {
+	if fn.dealAces {
+		MoveNamedCard(fn.stock, CLUB, 1, fn.foundations[0])
+		MoveNamedCard(fn.stock, DIAMOND, 1, fn.foundations[1])
+		MoveNamedCard(fn.stock, HEART, 1, fn.foundations[2])
+		MoveNamedCard(fn.stock, SPADE, 1, fn.foundations[3])
+	}
+	for _, t := range fn.tableaux {
+		for i := 0; i < 3; i++ {
+			MoveCard(fn.stock, t)
+		}
+		if fn.buryKings {
+			t.BuryCards(13)
+		}
+	}
+	if fn.stock.Len() > 0 {
+		println("*** still", fn.stock.Len(), "cards in Stock")
+	}
+	TheBaize.SetRedeals(fn.redeals)
+}
+
+func (*Fan) AfterMove() {
+}
+
+func (*Fan) TailMoveError(tail []*Card) (bool, error) {
+	// attempt to move more than one card will be caught before this
+	return true, nil
+}
+
+func (fn *Fan) TailAppendError(dst Pile, tail []*Card) (bool, error) {
+	switch (dst).(type) {
+	case *Foundation:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		} else {
+			return CardPair{dst.Peek(), tail[0]}.Compare_UpSuit()
+		}
+	case *Tableau:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		} else {
+			if fn.maxTabLen > 0 && dst.Len() >= fn.maxTabLen {
+				return false, errors.New("That fan is full")
+			}
+			return fn.tabCompareFunc(CardPair{dst.Peek(), tail[0]})
+		}
+	}
+	return true, nil
+}
+
+func (fn *Fan) UnsortedPairs(pile Pile) int {
+	return UnsortedPairs(pile, fn.tabCompareFunc)
+}
+
+func (*Fan) TailTapped(tail []*Card) {
+	tail[0].Owner().TailTapped(tail)
+}
+
+func (fn *Fan) PileTapped(pile Pile) {
+	if pile == fn.stock {
+		RedealTableaux(fn.tableaux, fn.stock, 3)
+	}
+}
@@ -10,6 +10,7 @@ type Klondike struct {
 	ScriptBase
 	draw, recycles int
 	thoughtful     bool
+	jokers         int // jokers per pack, which are wild
 }
 
 func (*Klondike) Info() *VariantInfo {
@@ -25,7 +26,7 @@ func (kl *Klondike) BuildPiles() {
 	if kl.draw == 0 {
 		kl.draw = 1
 	}
-	kl.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	kl.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, kl.jokers)
 	kl.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
 
 	kl.foundations = nil
@@ -55,7 +56,7 @@ func (kl *Klondike) StartGame() {
 		dealDown++
 		MoveCard(kl.stock, pile)
 	}
-	TheBaize.SetRecycles(kl.recycles)
+	TheBaize.SetRecycles(TheBaize.scoringModel().RecycleLimit(kl.draw, kl.recycles))
 	for i := 0; i < kl.draw; i++ {
 		MoveCard(kl.stock, kl.waste)
 	}
@@ -74,12 +75,8 @@ func (*Klondike) TailMoveError(tail []*Card) (bool, error) {
 	// why the pretty asterisks? google method pointer receivers in interfaces; *Tableau is a different type to Tableau
 	switch (pile).(type) {
 	case *Tableau:
-		var cpairs CardPairs = NewCardPairs(tail)
-		// cpairs.Print()
-		for _, pair := range cpairs {
-			if ok, err := pair.Compare_DownAltColor(); !ok {
-				return false, err
-			}
+		if ok, err := TailConformant(tail, CardPair.Compare_DownAltColor); !ok {
+			return false, err
 		}
 	}
 	return true, nil
@@ -98,7 +95,7 @@ func (*Klondike) TailAppendError(dst Pile, tail []*Card) (bool, error) {
 		if dst.Empty() {
 			return Compare_Empty(dst, tail[0])
 		} else {
-			return CardPair{dst.Peek(), tail[0]}.Compare_DownAltColor()
+			return AppendConformant(dst, tail, CardPair.Compare_DownAltColor)
 		}
 	}
 	return true, nil
@@ -0,0 +1,179 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"errors"
+	"image"
+	"math/rand"
+)
+
+type Montana struct {
+	ScriptBase
+	moon    bool // Aces are kept at the start of each row, rather than being removed
+	redMoon bool // Aces are placed before the deal, leaving the gaps next to them
+	rows    [][]*GridCell
+}
+
+func (*Montana) Info() *VariantInfo {
+	return &VariantInfo{
+		windowShape: "landscape",
+		wikipedia:   "https://en.wikipedia.org/wiki/Gaps",
+		relaxable:   false,
+	}
+}
+
+func (mt *Montana) BuildPiles() {
+
+	var columns int
+	if mt.moon {
+		mt.stock = NewStock(image.Point{0, 4}, FAN_NONE, 1, 4, nil, 0)
+		columns = 14
+	} else {
+		// Montana is played without the Aces, which leaves four gaps in the grid
+		mt.stock = NewStock(image.Point{0, 4}, FAN_NONE, 1, 4, &[14]bool{false, false, true, true, true, true, true, true, true, true, true, true, true, true}, 0)
+		columns = 13
+	}
+
+	mt.grid = nil
+	mt.rows = nil
+	for y := 0; y < 4; y++ {
+		var row []*GridCell
+		var neighbour *GridCell
+		for x := 0; x < columns; x++ {
+			gc := NewGridCell(image.Point{x, y}, neighbour)
+			if neighbour == nil {
+				if mt.moon {
+					gc.SetLabel("A")
+				} else {
+					gc.SetLabel("2")
+				}
+			}
+			row = append(row, gc)
+			neighbour = gc
+		}
+		mt.grid = append(mt.grid, row...)
+		mt.rows = append(mt.rows, row)
+	}
+}
+
+func (mt *Montana) StartGame() {
+	switch {
+	case mt.redMoon:
+		for y, suit := range []int{CLUB, DIAMOND, HEART, SPADE} {
+			MoveNamedCard(mt.stock, suit, 1, mt.rows[y][0])
+		}
+		for _, row := range mt.rows {
+			for _, gc := range row[2:] {
+				MoveCard(mt.stock, gc)
+			}
+		}
+	case mt.moon:
+		for _, row := range mt.rows {
+			for _, gc := range row[1:] {
+				MoveCard(mt.stock, gc)
+			}
+		}
+		// move the Aces to the start of the rows, leaving a gap where each one was
+		var y int
+		for _, row := range mt.rows {
+			for _, gc := range row[1:] {
+				if gc.Peek().Ordinal() == 1 {
+					MoveCard(gc, mt.rows[y][0])
+					y++
+				}
+			}
+		}
+	default:
+		// deal as if the Aces were there, then leave their places empty
+		var gaps = map[int]bool{}
+		for _, i := range rand.Perm(len(mt.grid))[:len(mt.grid)-mt.stock.Len()] {
+			gaps[i] = true
+		}
+		for i, gc := range mt.grid {
+			if !gaps[i] {
+				MoveCard(mt.stock, gc)
+			}
+		}
+	}
+	if mt.stock.Len() > 0 {
+		println("*** still", mt.stock.Len(), "cards in Stock")
+	}
+	TheBaize.SetRedeals(2)
+}
+
+func (*Montana) AfterMove() {
+}
+
+func (*Montana) TailMoveError(tail []*Card) (bool, error) {
+	return true, nil
+}
+
+func (*Montana) TailAppendError(dst Pile, tail []*Card) (bool, error) {
+	switch (dst).(type) {
+	case *GridCell:
+		neighbour := dst.(*GridCell).Neighbour()
+		if neighbour == nil {
+			return Compare_Empty(dst, tail[0])
+		}
+		if neighbour.Empty() {
+			return false, errors.New("Cannot move a card after a gap")
+		}
+		return CardPair{neighbour.Peek(), tail[0]}.Compare_UpSuit()
+	}
+	return true, nil
+}
+
+func (*Montana) UnsortedPairs(pile Pile) int {
+	// GridCell does it's own counting
+	return 0
+}
+
+func (*Montana) TailTapped(tail []*Card) {
+	tail[0].Owner().TailTapped(tail)
+}
+
+func (mt *Montana) PileTapped(pile Pile) {
+	if pile == mt.stock {
+		mt.redeal()
+	}
+}
+
+// sortedLength returns the number of cards at the start of a row that are in their final places
+func sortedLength(row []*GridCell) int {
+	for i, gc := range row {
+		if gc.Empty() || !gc.Complete() {
+			return i
+		}
+	}
+	return len(row)
+}
+
+// redeal gathers up all the cards that are not in their final places, shuffles them,
+// and deals them back, leaving a gap after the sorted cards at the start of each row
+func (mt *Montana) redeal() {
+	if TheBaize.Redeals() == 0 {
+		TheUI.Toast("No more redeals")
+		return
+	}
+	var sorted []int
+	for _, row := range mt.rows {
+		n := sortedLength(row)
+		for _, gc := range row[n:] {
+			if !gc.Empty() {
+				MoveCard(gc, mt.stock)
+			}
+		}
+		sorted = append(sorted, n)
+	}
+	mt.stock.Reshuffle()
+	for y, row := range mt.rows {
+		if sorted[y]+1 < len(row) {
+			for _, gc := range row[sorted[y]+1:] {
+				MoveCard(mt.stock, gc)
+			}
+		}
+	}
+	TheBaize.SetRedeals(TheBaize.Redeals() - 1)
+	toastRedeals()
+}
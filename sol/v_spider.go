@@ -11,6 +11,7 @@ import (
 type Spider struct {
 	ScriptBase
 	packs, suits int
+	jokers       int // jokers per pack, which are wild
 }
 
 func (*Spider) Info() *VariantInfo {
@@ -23,10 +24,11 @@ func (*Spider) Info() *VariantInfo {
 
 func (sp *Spider) BuildPiles() {
 
-	sp.stock = NewStock(image.Point{0, 0}, FAN_NONE, sp.packs, sp.suits, nil, 0)
+	sp.stock = NewStock(image.Point{0, 0}, FAN_NONE, sp.packs, sp.suits, nil, sp.jokers)
 
+	// one discard for each set of thirteen cards
 	sp.discards = nil
-	for x := 2; x < 10; x++ {
+	for x := 2; x < 2+sp.packs*sp.suits; x++ {
 		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
 		sp.discards = append(sp.discards, d)
 	}
@@ -75,10 +77,8 @@ func (*Spider) TailMoveError(tail []*Card) (bool, error) {
 	// why the pretty asterisks? google method pointer receivers in interfaces; *Tableau is a different type to Tableau
 	switch (pile).(type) {
 	case *Tableau:
-		for _, pair := range NewCardPairs(tail) {
-			if ok, err := pair.Compare_DownSuit(); !ok {
-				return false, err
-			}
+		if ok, err := TailConformant(tail, CardPair.Compare_DownSuit); !ok {
+			return false, err
 		}
 	}
 	return true, nil
@@ -88,18 +88,22 @@ func (*Spider) TailAppendError(dst Pile, tail []*Card) (bool, error) {
 	// why the pretty asterisks? google method pointer receivers in interfaces; *Tableau is a different type to Tableau
 	switch (dst).(type) {
 	case *Discard:
-		if tail[0].Ordinal() != 13 {
-			return false, errors.New("Can only discard starting from a King")
-		}
-		for _, pair := range NewCardPairs(tail) {
-			if ok, err := pair.Compare_DownSuit(); !ok {
-				return false, err
+		// a joker at the start of the set is ignored, so the first real card must be a King
+		for _, c := range tail {
+			if !c.Wild() {
+				if c.Ordinal() != 13 {
+					return false, errors.New("Can only discard starting from a King")
+				}
+				break
 			}
 		}
+		if ok, err := TailConformant(tail, CardPair.Compare_DownSuit); !ok {
+			return false, err
+		}
 	case *Tableau:
 		if dst.Empty() {
 		} else {
-			return CardPair{dst.Peek(), tail[0]}.Compare_Down()
+			return AppendConformant(dst, tail, CardPair.Compare_Down)
 		}
 	}
 	return true, nil
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/schriftbank"
+)
+
+// Calendar shows a month, with the days that were won or lost marked; it doesn't do anything when tapped
+type Calendar struct {
+	WidgetBase
+	month time.Time      // the first day of the month
+	days  map[int]string // day of the month to "won" or "lost"
+	today int            // day of the month to ring, or 0
+}
+
+func (w *Calendar) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+	dc.SetFontFace(schriftbank.RobotoRegular14)
+
+	// nota bene - text is drawn with y as a baseline
+	dc.SetRGBA(1, 1, 1, 1)
+	dc.DrawString(w.month.Format("January 2006"), 0, 16)
+
+	cell := float64(w.width) / 7
+	const top = 24
+	for i, d := range []string{"M", "T", "W", "T", "F", "S", "S"} {
+		dc.SetRGBA(1, 1, 1, 0.5)
+		dc.DrawStringAnchored(d, cell*(float64(i)+0.5), top+12, 0.5, 0)
+	}
+	// weeks start on Monday
+	offset := (int(w.month.Weekday()) + 6) % 7
+	last := w.month.AddDate(0, 1, -1).Day()
+	for day := 1; day <= last; day++ {
+		col, row := (day-1+offset)%7, (day-1+offset)/7
+		x, y := cell*float64(col), float64(top+20)+cell*0.6*float64(row)
+		switch w.days[day] {
+		case "won":
+			dc.SetColor(chartWonColor)
+			dc.DrawCircle(x+cell/2, y+cell*0.3, cell*0.28)
+			dc.Fill()
+		case "lost":
+			dc.SetColor(chartLostColor)
+			dc.DrawCircle(x+cell/2, y+cell*0.3, cell*0.28)
+			dc.Fill()
+		}
+		if day == w.today {
+			dc.SetRGBA(1, 1, 1, 1)
+			dc.DrawCircle(x+cell/2, y+cell*0.3, cell*0.28)
+			dc.Stroke()
+		}
+		if w.days[day] == "" {
+			dc.SetRGBA(1, 1, 1, 1)
+		} else {
+			dc.SetRGBA(0, 0, 0, 1)
+		}
+		dc.DrawStringAnchored(fmt.Sprint(day), x+cell/2, y+cell*0.3, 0.5, 0.35)
+	}
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewCalendar creates a Calendar of the month that t is in, marking days with the results in days
+func NewCalendar(parent Container, t time.Time, days map[int]string, today int) *Calendar {
+	width, _ := parent.Size()
+	width -= 48
+	month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+	// room for the title, the days of the week, and up to six weeks
+	height := 44 + int(float64(width)/7*0.6*6)
+	// widget x, y will be set by LayoutWidgets
+	w := &Calendar{
+		WidgetBase: WidgetBase{parent: parent, img: nil, width: width, height: height},
+		month:      month, days: days, today: today}
+	w.Activate()
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *Calendar) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *Calendar) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *Calendar) NotifyCallback(v input.StrokeEvent) {
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/schriftbank"
+)
+
+var (
+	chartWonColor  = color.RGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0xff} // Gold
+	chartLostColor = color.RGBA{R: 0xc0, G: 0xc0, B: 0xc0, A: 0xff} // Silver
+)
+
+// Chart draws some numbers as bars; it doesn't do anything when tapped
+type Chart struct {
+	WidgetBase
+	title  string
+	values []int
+	labels []string // under the first, middle and last bars; optional
+	colors []color.RGBA
+}
+
+func (w *Chart) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+
+	// nota bene - text is drawn with y as a baseline
+	dc.SetRGBA(1, 1, 1, 0.5)
+	dc.SetFontFace(schriftbank.RobotoRegular14)
+	dc.DrawString(w.title, 0, 16)
+
+	const top, bottom = 24, 18 // room for the title and the labels
+	var max int
+	for _, v := range w.values {
+		if v > max {
+			max = v
+		}
+	}
+	if len(w.values) == 0 || max == 0 {
+		dc.DrawString("Nothing to show yet", 0, float64(w.height)/2)
+		return ebiten.NewImageFromImage(dc.Image())
+	}
+	barWidth := float64(w.width) / float64(len(w.values))
+	plotHeight := float64(w.height - top - bottom)
+	for i, v := range w.values {
+		h := plotHeight * float64(v) / float64(max)
+		dc.SetColor(w.colors[i])
+		dc.DrawRectangle(float64(i)*barWidth+1, float64(top)+plotHeight-h, barWidth-2, h)
+		dc.Fill()
+	}
+	dc.SetRGBA(1, 1, 1, 0.5)
+	for i, label := range w.labels {
+		x := float64(i) * float64(w.width) / 2
+		dc.DrawStringAnchored(label, x, float64(w.height)-4, float64(i)/2, 0)
+	}
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+func newChart(parent Container, title string, values []int, labels []string, colors []color.RGBA) *Chart {
+	width, _ := parent.Size()
+	// widget x, y will be set by LayoutWidgets
+	w := &Chart{
+		WidgetBase: WidgetBase{parent: parent, img: nil, width: width - 48, height: 120},
+		title:      title, values: values, labels: labels, colors: colors}
+	w.Activate()
+	return w
+}
+
+// NewHistogram creates a Chart showing how many games got to each percent complete;
+// counts[i] is the number of games that got to between i*10 and i*10+9 percent, and counts[10] is the games won
+func NewHistogram(parent Container, counts []int) *Chart {
+	colors := make([]color.RGBA, len(counts))
+	for i := range colors {
+		colors[i] = chartLostColor
+	}
+	if len(colors) > 0 {
+		colors[len(colors)-1] = chartWonColor
+	}
+	return newChart(parent, "Percent complete", counts, []string{"0%", "50%", "100%"}, colors)
+}
+
+// NewSparkline creates a Chart showing the percent complete of recent games, oldest first; 100 is a win
+func NewSparkline(parent Container, percents []int) *Chart {
+	colors := make([]color.RGBA, len(percents))
+	for i, pc := range percents {
+		if pc == 100 {
+			colors[i] = chartWonColor
+		} else {
+			colors[i] = chartLostColor
+		}
+	}
+	return newChart(parent, fmt.Sprintf("Last %d games", len(percents)), percents, nil, colors)
+}
+
+// Activate tells the input we need notifications
+func (w *Chart) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *Chart) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *Chart) NotifyCallback(v input.StrokeEvent) {
+}
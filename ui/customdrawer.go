@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"oddstream.games/gosol/schriftbank"
+)
+
+// CustomDrawer lets the user change the parameters of a variant
+type CustomDrawer struct {
+	DrawerBase
+}
+
+// NewCustomDrawer creates the CustomDrawer object; it starts life off screen to the left
+func NewCustomDrawer() *CustomDrawer {
+	d := &CustomDrawer{DrawerBase: DrawerBase{x: -300, y: 48, width: 300}} // height will be set when drawn
+	return d
+}
+
+// ShowCustomDrawer makes the custom variant drawer visible, with name (or placeholder, if it's empty) as the name to save it under.
+// If it's already showing, the widgets are rebuilt in place, because changing one setting may change the choices for another
+func (u *UI) ShowCustomDrawer(name, placeholder string, settings []SpinnerSetting) {
+	con := u.VisibleDrawer()
+	if con != nil && con != u.customDrawer {
+		con.Hide()
+	}
+	u.customDrawer.widgets = nil
+	for _, s := range settings {
+		u.customDrawer.widgets = append(u.customDrawer.widgets, NewSpinner(u.customDrawer, s))
+	}
+	u.customDrawer.widgets = append(u.customDrawer.widgets,
+		NewTextInput(u.customDrawer, name, placeholder, "Custom Name", ""),
+		NewLabel(u.customDrawer, 0, "Save and play", schriftbank.RobotoMedium24, "Custom"),
+		NewLabel(u.customDrawer, 0, "Share", schriftbank.RobotoMedium24, "Custom"))
+	u.customDrawer.LayoutWidgets()
+	if con != u.customDrawer {
+		u.customDrawer.ResetScroll()
+		u.customDrawer.Show()
+	}
+}
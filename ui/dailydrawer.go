@@ -0,0 +1,40 @@
+package ui
+
+import "time"
+
+// DailyDrawer shows today's daily challenge, how the user has got on with the past ones, and a calendar of them
+type DailyDrawer struct {
+	DrawerBase
+}
+
+// DailyMonth is the results of the daily challenges in one month
+type DailyMonth struct {
+	Month time.Time      // any time in the month
+	Days  map[int]string // day of the month to "won" or "lost"
+	Today int            // day of the month that is today, or 0
+}
+
+// NewDailyDrawer creates the DailyDrawer object; it starts life off screen to the left
+func NewDailyDrawer() *DailyDrawer {
+	d := &DailyDrawer{DrawerBase: DrawerBase{x: -300, y: 48, width: 300}} // height will be set when drawn
+	return d
+}
+
+// ShowDailyDrawer makes the daily challenge drawer visible; the entries go above the calendars
+func (u *UI) ShowDailyDrawer(entries []ListEntry, months []DailyMonth) {
+	con := u.VisibleDrawer()
+	if con != nil {
+		con.Hide()
+	}
+	d := u.dailyDrawer
+	d.widgets = nil
+	for _, e := range entries {
+		d.widgets = append(d.widgets, NewListItem(d, e))
+	}
+	for _, m := range months {
+		d.widgets = append(d.widgets, NewCalendar(d, m.Month, m.Days, m.Today))
+	}
+	d.ResetScroll()
+	d.LayoutWidgets()
+	d.Show()
+}
@@ -60,6 +60,7 @@ func (f *FAB) Deactivate() {
 
 type FABBar struct {
 	BarBase
+	lift int // extra room to leave at the bottom, for the timeline
 }
 
 func (fb *FABBar) createImg() *ebiten.Image {
@@ -82,7 +83,7 @@ func NewFABBar() *FABBar {
 func (fb *FABBar) Layout(outsideWidth, outsideHeight int) (int, int) {
 	// override BarBase.Layout to get position near bottom right of screen
 	fb.x = outsideWidth - fb.width - (fb.width / 2)
-	fb.y = outsideHeight - fb.height - (fb.height / 2) - 24 // statusbar is 24 high
+	fb.y = outsideHeight - fb.height - (fb.height / 2) - 24 - fb.lift // statusbar is 24 high
 	println("FABBar.Layout() Window=", outsideWidth, outsideHeight, "FAB=", fb.x, fb.y)
 	return outsideWidth, outsideHeight
 }
@@ -0,0 +1,39 @@
+package ui
+
+// ListDrawer shows a list of ListEntry, like the bookmarks or the achievements.
+// There's one for each list, made the first time it's shown, see ShowListDrawer
+type ListDrawer struct {
+	DrawerBase
+}
+
+// NewListDrawer creates a ListDrawer object; it starts life off screen to the left
+func NewListDrawer() *ListDrawer {
+	d := &ListDrawer{DrawerBase: DrawerBase{x: -300, y: 48, width: 300}} // height will be set when drawn
+	return d
+}
+
+// ShowListDrawer makes the list drawer called name visible, showing entries
+func (u *UI) ShowListDrawer(name string, entries []ListEntry) {
+	d, ok := u.listDrawers[name]
+	if !ok {
+		d = NewListDrawer()
+		u.listDrawers[name] = d
+		u.drawers = append(u.drawers, d)
+		u.containers = append(u.containers, d)
+	}
+	con := u.VisibleDrawer()
+	if con != nil {
+		con.Hide()
+	}
+	d.widgets = nil
+	for _, e := range entries {
+		if e.Placeholder != "" {
+			d.widgets = append(d.widgets, NewTextInput(d, e.Text, e.Placeholder, e.RequestType, e.Submit))
+		} else {
+			d.widgets = append(d.widgets, NewListItem(d, e))
+		}
+	}
+	d.ResetScroll()
+	d.LayoutWidgets()
+	d.Show()
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/schriftbank"
+	"oddstream.games/gosol/util"
+)
+
+// ListEntry describes one line of a list in a drawer
+type ListEntry struct {
+	Icon        string // optional
+	Text        string
+	Detail      string // optional, shown in small print under the text
+	RequestType string // sent in the ChangeRequest when tapped; if empty, the entry is just a heading
+	Data        string // sent in the ChangeRequest when tapped
+	// Placeholder makes the entry a line to type into, holding Text; every change is sent as RequestType,
+	// and Enter sends Submit (see TextInput)
+	Placeholder string
+	Submit      string
+}
+
+// ListItem is a ListEntry, drawn like a NavItem
+type ListItem struct {
+	WidgetBase
+	entry ListEntry
+}
+
+func (w *ListItem) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+
+	if w.entry.Icon != "" {
+		img, ok := IconMap[w.entry.Icon]
+		if !ok || img == nil {
+			log.Fatal(w.entry.Icon, " not in icon map")
+		}
+		dc.DrawImage(img, 0, w.height/4)
+	}
+
+	// nota bene - text is drawn with y as a baseline
+	dc.SetRGBA(1, 1, 1, 1)
+	dc.SetFontFace(schriftbank.RobotoMedium24)
+	if w.entry.Detail == "" {
+		dc.DrawString(w.entry.Text, 48, float64(w.height)*0.8)
+	} else {
+		dc.DrawString(w.entry.Text, 48, 22)
+		dc.SetRGBA(1, 1, 1, 0.5)
+		dc.SetFontFace(schriftbank.RobotoRegular14)
+		dc.DrawString(w.entry.Detail, 48, 42)
+	}
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewListItem creates a new ListItem
+func NewListItem(parent Container, entry ListEntry) *ListItem {
+	width, _ := parent.Size()
+	// widget x, y will be set by LayoutWidgets
+	w := &ListItem{
+		WidgetBase: WidgetBase{parent: parent, img: nil, width: width - 24, height: 48},
+		entry:      entry,
+	}
+	w.Activate()
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *ListItem) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *ListItem) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *ListItem) NotifyCallback(v input.StrokeEvent) {
+	if w.disabled || w.entry.RequestType == "" {
+		return
+	}
+	switch v.Event {
+	case input.Tap:
+		if util.InRect(v.X, v.Y, w.OffsetRect) {
+			cmdFn(ChangeRequest{ChangeRequested: w.entry.RequestType, Data: w.entry.Data})
+		}
+	}
+}
@@ -18,11 +18,18 @@ func NewNavDrawer() *NavDrawer {
 		NewNavItem(n, "star", "New deal", ebiten.KeyN),
 		NewNavItem(n, "restore", "Restart deal", ebiten.KeyR),
 		NewNavItem(n, "search", "Find game...", ebiten.KeyF),
+		NewNavItem(n, "star", "Daily challenge...", ebiten.KeyD),
 		NewNavItem(n, "bookmark_add", "Bookmark", ebiten.KeyS),
 		NewNavItem(n, "bookmark", "Goto bookmark", ebiten.KeyL),
+		NewNavItem(n, "restore", "Bookmarks and branches...", ebiten.KeyB),
+		NewNavItem(n, "undo", "Timeline", ebiten.KeyT),
+		NewNavItem(n, "done", "Saved games...", ebiten.KeyF7),
 		NewNavItem(n, "info", "Wikipedia...", ebiten.KeyF1),
 		NewNavItem(n, "list", "Statistics", ebiten.KeyF2),
+		NewNavItem(n, "star", "Achievements...", ebiten.KeyF9),
 		NewNavItem(n, "settings", "Settings...", ebiten.KeyF3),
+		NewNavItem(n, "menu", "Profiles...", ebiten.KeyP),
+		NewNavItem(n, "done_all", "Custom variant...", ebiten.KeyF4),
 	}
 	// don't know how to ask a browser window to close
 	// if runtime.GOARCH != "wasm" {
@@ -13,7 +13,7 @@ func NewSettingsDrawer() *SettingsDrawer {
 }
 
 // ShowSettingsDrawer makes the card back picker visible
-func (u *UI) ShowSettingsDrawer(booleanSettings map[string]bool) {
+func (u *UI) ShowSettingsDrawer(booleanSettings map[string]bool, spinnerSettings []SpinnerSetting) {
 	con := u.VisibleDrawer()
 	if con == u.settingsDrawer {
 		return
@@ -31,6 +31,9 @@ func (u *UI) ShowSettingsDrawer(booleanSettings map[string]bool) {
 		NewCheckbox(u.settingsDrawer, "Mirror baize", booleanSettings["MirrorBaize"]),
 		NewCheckbox(u.settingsDrawer, "Mute sounds", booleanSettings["Mute"]),
 	}
+	for _, s := range spinnerSettings {
+		u.settingsDrawer.widgets = append(u.settingsDrawer.widgets, NewSpinner(u.settingsDrawer, s))
+	}
 	u.settingsDrawer.LayoutWidgets()
 	u.settingsDrawer.Show()
 }
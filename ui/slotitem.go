@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/schriftbank"
+	"oddstream.games/gosol/util"
+)
+
+// SlotItem shows a saved game; tapping it loads the game, tapping the cross twice deletes it
+type SlotItem struct {
+	WidgetBase
+	info SlotInfo
+}
+
+const slotThumbnailWidth = 96
+
+func (w *SlotItem) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+
+	if w.info.Thumbnail != nil {
+		dc.DrawImage(w.info.Thumbnail, 0, 0)
+	} else {
+		dc.SetRGBA(1, 1, 1, 0.3)
+		dc.DrawRectangle(0.5, 0.5, slotThumbnailWidth-1, float64(w.height)-1)
+		dc.Stroke()
+	}
+
+	// nota bene - text is drawn with y as a baseline
+	x := float64(slotThumbnailWidth + 8)
+	dc.SetRGBA(1, 1, 1, 1)
+	dc.SetFontFace(schriftbank.RobotoMedium24)
+	dc.DrawString(w.info.Name, x, 24)
+	dc.SetRGBA(1, 1, 1, 0.5)
+	dc.SetFontFace(schriftbank.RobotoRegular14)
+	dc.DrawString(w.info.Variant, x, 44)
+	if w.info.DeletePending {
+		dc.SetRGBA(1, 1, 1, 1)
+	}
+	dc.DrawString(w.info.Details, x, 62)
+
+	if img, ok := IconMap["close"]; ok && img != nil {
+		dc.DrawImage(img, w.width-24, 0)
+	}
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewSlotItem creates a new SlotItem
+func NewSlotItem(parent Container, info SlotInfo) *SlotItem {
+	width, _ := parent.Size()
+	// widget x, y will be set by LayoutWidgets
+	w := &SlotItem{
+		WidgetBase: WidgetBase{parent: parent, img: nil, width: width - 48, height: 72},
+		info:       info,
+	}
+	w.Activate()
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *SlotItem) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *SlotItem) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *SlotItem) NotifyCallback(v input.StrokeEvent) {
+	if w.disabled {
+		return
+	}
+	switch v.Event {
+	case input.Tap:
+		if util.InRect(v.X, v.Y, w.OffsetRect) {
+			_, _, x1, _ := w.OffsetRect()
+			if v.X > x1-24 {
+				if w.info.DeletePending {
+					cmdFn(ChangeRequest{ChangeRequested: "Delete slot confirmed", Data: w.info.Name})
+				} else {
+					cmdFn(ChangeRequest{ChangeRequested: "Delete slot", Data: w.info.Name})
+				}
+			} else {
+				cmdFn(ChangeRequest{ChangeRequested: "Load slot", Data: w.info.Name})
+			}
+		}
+	}
+}
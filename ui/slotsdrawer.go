@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"image"
+
+	"oddstream.games/gosol/schriftbank"
+)
+
+// SlotInfo describes a saved game, for the SlotsDrawer
+type SlotInfo struct {
+	Name      string
+	Variant   string
+	Details   string      // when it was saved, and how far it had got
+	Thumbnail image.Image // may be nil
+	// DeletePending is true if the cross has been tapped once, so the next tap deletes it
+	DeletePending bool
+}
+
+// SlotsDrawer lists the saved games, so one can be loaded or deleted
+type SlotsDrawer struct {
+	DrawerBase
+}
+
+// NewSlotsDrawer creates the SlotsDrawer object; it starts life off screen to the left
+func NewSlotsDrawer() *SlotsDrawer {
+	d := &SlotsDrawer{DrawerBase: DrawerBase{x: -300, y: 48, width: 300}} // height will be set when drawn
+	return d
+}
+
+// ShowSlotsDrawer makes the saved games drawer visible, with name (or placeholder, if it's empty)
+// as the name to save the current game under.
+// If it's already showing (because a game was just deleted), it's rebuilt in place
+func (u *UI) ShowSlotsDrawer(name, placeholder string, slots []SlotInfo) {
+	con := u.VisibleDrawer()
+	if con != nil && con != u.slotsDrawer {
+		con.Hide()
+	}
+	u.slotsDrawer.widgets = []Widget{
+		NewTextInput(u.slotsDrawer, name, placeholder, "Slot name", "Save slot"),
+		NewLabel(u.slotsDrawer, 0, "Save current game", schriftbank.RobotoMedium24, "Save slot"),
+	}
+	for _, si := range slots {
+		u.slotsDrawer.widgets = append(u.slotsDrawer.widgets, NewSlotItem(u.slotsDrawer, si))
+	}
+	u.slotsDrawer.LayoutWidgets()
+	if con != u.slotsDrawer {
+		u.slotsDrawer.ResetScroll()
+		u.slotsDrawer.Show()
+	}
+}
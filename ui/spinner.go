@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/schriftbank"
+	"oddstream.games/gosol/util"
+)
+
+// Spinner shows a setting and it's value; tapping it steps through the values
+type Spinner struct {
+	WidgetBase
+	text        string
+	options     []string
+	current     int
+	requestType string
+}
+
+// SpinnerSetting describes the contents of a Spinner
+type SpinnerSetting struct {
+	Text        string   // the name of the setting, shown above the value
+	Options     []string // the values to step through
+	Current     string   // the value to show first
+	RequestType string   // sent in the ChangeRequest when the value changes
+}
+
+func (w *Spinner) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+
+	// nota bene - text is drawn with y as a baseline
+	dc.SetRGBA(1, 1, 1, 0.5)
+	dc.SetFontFace(schriftbank.RobotoRegular14)
+	dc.DrawString(w.text, 0, 16)
+
+	dc.SetRGBA(1, 1, 1, 1)
+	dc.SetFontFace(schriftbank.RobotoMedium24)
+	dc.DrawString("▸ "+w.options[w.current], 0, float64(w.height)*0.9)
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewSpinner creates a new Spinner
+func NewSpinner(parent Container, setting SpinnerSetting) *Spinner {
+	width, _ := parent.Size()
+	w := &Spinner{
+		WidgetBase:  WidgetBase{parent: parent, img: nil, x: 0, y: 0, width: width, height: 48},
+		text:        setting.Text,
+		options:     setting.Options,
+		requestType: setting.RequestType,
+	}
+	for i, opt := range w.options {
+		if opt == setting.Current {
+			w.current = i
+			break
+		}
+	}
+	w.Activate()
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *Spinner) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notofications
+func (w *Spinner) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *Spinner) NotifyCallback(v input.StrokeEvent) {
+	if w.disabled {
+		return
+	}
+	switch v.Event {
+	case input.Tap:
+		if util.InRect(v.X, v.Y, w.OffsetRect) {
+			w.current = (w.current + 1) % len(w.options)
+			w.img = w.createImg()
+			cmdFn(ChangeRequest{ChangeRequested: w.requestType, Data: w.options[w.current]})
+		}
+	}
+}
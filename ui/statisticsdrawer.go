@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"oddstream.games/gosol/schriftbank"
+)
+
+// StatisticsDrawer shows a table of the statistics of every variant played,
+// or the details of one of them
+type StatisticsDrawer struct {
+	DrawerBase
+}
+
+// StatisticsDetail is everything shown about one variant
+type StatisticsDetail struct {
+	Variant   string
+	Lines     []string    // of text
+	Histogram []int       // see NewHistogram
+	Recent    []int       // see NewSparkline
+	Entries   []ListEntry // things to do, like resetting the statistics
+}
+
+// NewStatisticsDrawer creates the StatisticsDrawer object; it starts life off screen to the left.
+// It's wider than the other drawers, to fit the table in
+func NewStatisticsDrawer() *StatisticsDrawer {
+	d := &StatisticsDrawer{DrawerBase: DrawerBase{x: -600, y: 48, width: 600}} // height will be set when drawn
+	return d
+}
+
+// showStatistics replaces the contents of the drawer, showing it if it isn't already
+func (u *UI) showStatistics(widgets []Widget) {
+	con := u.VisibleDrawer()
+	if con != nil && con != u.statisticsDrawer {
+		con.Hide()
+	}
+	u.statisticsDrawer.widgets = widgets
+	u.statisticsDrawer.ResetScroll()
+	u.statisticsDrawer.LayoutWidgets()
+	if con != u.statisticsDrawer {
+		u.statisticsDrawer.Show()
+	}
+}
+
+// ShowStatisticsTable shows the statistics of every variant as a table; tapping a heading sorts by that column,
+// tapping a row shows the details. The first cell of each row is the variant name.
+// The entries go under the table
+func (u *UI) ShowStatisticsTable(headings []string, widths []int, rows [][]string, entries []ListEntry) {
+	d := u.statisticsDrawer
+	widgets := []Widget{NewTableRow(d, headings, widths, schriftbank.RobotoMedium14, "Statistics sort", "")}
+	for _, row := range rows {
+		widgets = append(widgets, NewTableRow(d, row, widths, schriftbank.RobotoRegular14, "Statistics detail", row[0]))
+	}
+	for _, e := range entries {
+		widgets = append(widgets, NewListItem(d, e))
+	}
+	u.showStatistics(widgets)
+}
+
+// ShowStatisticsDetail shows the details of one variant
+func (u *UI) ShowStatisticsDetail(detail StatisticsDetail) {
+	d := u.statisticsDrawer
+	widgets := []Widget{NewLabel(d, 0, detail.Variant, schriftbank.RobotoMedium24, "")}
+	for _, line := range detail.Lines {
+		widgets = append(widgets, NewText(d, line))
+	}
+	widgets = append(widgets, NewHistogram(d, detail.Histogram), NewSparkline(d, detail.Recent))
+	for _, e := range detail.Entries {
+		widgets = append(widgets, NewListItem(d, e))
+	}
+	u.showStatistics(widgets)
+}
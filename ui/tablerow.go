@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/util"
+)
+
+// TableRow is a row of a table, with each cell drawn in a column of fixed width
+type TableRow struct {
+	WidgetBase
+	cells       []string
+	widths      []int
+	fontFace    font.Face
+	requestType string
+	data        string
+}
+
+func (w *TableRow) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+	dc.SetRGBA(1, 1, 1, 1)
+	dc.SetFontFace(w.fontFace)
+	// nota bene - text is drawn with y as a baseline
+	var x int
+	for i, cell := range w.cells {
+		if i >= len(w.widths) {
+			break
+		}
+		dc.DrawString(cell, float64(x), float64(w.height)*0.75)
+		x += w.widths[i]
+	}
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewTableRow creates a new TableRow.
+// When tapped it sends a ChangeRequest with the data; if there isn't any data,
+// the number of the column that was tapped is sent instead, which is how headings work
+func NewTableRow(parent Container, cells []string, widths []int, fontFace font.Face, requestType string, data string) *TableRow {
+	var width int
+	for _, cw := range widths {
+		width += cw
+	}
+	// widget x, y will be set by LayoutWidgets
+	w := &TableRow{
+		WidgetBase: WidgetBase{parent: parent, img: nil, width: width, height: 24},
+		cells:      cells, widths: widths, fontFace: fontFace, requestType: requestType, data: data}
+	w.Activate()
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *TableRow) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *TableRow) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *TableRow) NotifyCallback(v input.StrokeEvent) {
+	if w.disabled || w.requestType == "" {
+		return
+	}
+	switch v.Event {
+	case input.Tap:
+		if !util.InRect(v.X, v.Y, w.OffsetRect) {
+			return
+		}
+		data := w.data
+		if data == "" {
+			x0, _, _, _ := w.OffsetRect()
+			column := 0
+			for x := x0 + w.widths[0]; column < len(w.widths)-1 && v.X >= x; x += w.widths[column] {
+				column++
+			}
+			data = strconv.Itoa(column)
+		}
+		cmdFn(ChangeRequest{ChangeRequested: w.requestType, Data: data})
+	}
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"unicode"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/schriftbank"
+	"oddstream.games/gosol/util"
+)
+
+// TextInput is a line of text that can be typed into, once it has been tapped.
+// Every change is sent as a ChangeRequest of requestType, so the game always knows what has been typed;
+// Enter sends submitType (if there is one), and both Enter and Escape stop the typing
+type TextInput struct {
+	WidgetBase
+	text        []rune
+	placeholder string // shown, dimmed, while nothing has been typed
+	requestType string
+	submitType  string
+}
+
+// textInputMax is the most runes a TextInput will take
+const textInputMax = 32
+
+// focusedInput is the TextInput being typed into, if any; only one can be at a time
+var focusedInput *TextInput
+
+// Typing is true while the user is typing into a TextInput, when keys shouldn't be taken as commands
+func (u *UI) Typing() bool {
+	return focusedInput != nil
+}
+
+func (w *TextInput) createImg() *ebiten.Image {
+	dc := gg.NewContext(w.width, w.height)
+
+	dc.SetRGBA(1, 1, 1, 0.5)
+	if focusedInput == w {
+		dc.SetRGBA(1, 1, 1, 1)
+	}
+	dc.DrawLine(0, float64(w.height)-1, float64(w.width), float64(w.height)-1)
+	dc.Stroke()
+
+	// nota bene - text is drawn with y as a baseline
+	dc.SetFontFace(schriftbank.RobotoMedium24)
+	text := string(w.text)
+	if text == "" && focusedInput != w {
+		dc.SetRGBA(1, 1, 1, 0.5)
+		text = w.placeholder
+	} else {
+		dc.SetRGBA(1, 1, 1, 1)
+	}
+	if focusedInput == w {
+		text += "|"
+	}
+	dc.DrawString(text, 0, float64(w.height)*0.7)
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewTextInput creates a new TextInput, holding text to start with
+func NewTextInput(parent Container, text, placeholder, requestType, submitType string) *TextInput {
+	if focusedInput != nil && focusedInput.parent == parent {
+		focusedInput = nil // the drawer is being rebuilt, and the old one thrown away
+	}
+	width, _ := parent.Size()
+	// widget x, y will be set by LayoutWidgets
+	w := &TextInput{
+		WidgetBase:  WidgetBase{parent: parent, img: nil, width: width - 48, height: 40},
+		text:        []rune(text),
+		placeholder: placeholder,
+		requestType: requestType,
+		submitType:  submitType,
+	}
+	w.Activate()
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *TextInput) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *TextInput) Deactivate() {
+	w.disabled = true
+	w.blur()
+}
+
+func (w *TextInput) focus() {
+	if focusedInput != nil && focusedInput != w {
+		focusedInput.blur()
+	}
+	focusedInput = w
+	w.img = w.createImg()
+}
+
+func (w *TextInput) blur() {
+	if focusedInput == w {
+		focusedInput = nil
+	}
+	w.img = w.createImg()
+}
+
+// Update takes whatever has been typed since the last tick
+func (w *TextInput) Update() {
+	if focusedInput != w {
+		return
+	}
+	changed := false
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if unicode.IsPrint(r) && len(w.text) < textInputMax {
+			w.text = append(w.text, r)
+			changed = true
+		}
+	}
+	// backspace repeats if it's held down
+	if d := inpututil.KeyPressDuration(ebiten.KeyBackspace); (d == 1 || d > 30 && d%3 == 0) && len(w.text) > 0 {
+		w.text = w.text[:len(w.text)-1]
+		changed = true
+	}
+	if changed {
+		w.img = w.createImg()
+		cmdFn(ChangeRequest{ChangeRequested: w.requestType, Data: string(w.text)})
+	}
+	// wait until the key is released, so it isn't then taken as a command
+	if inpututil.IsKeyJustReleased(ebiten.KeyEnter) || inpututil.IsKeyJustReleased(ebiten.KeyKPEnter) {
+		w.blur()
+		if w.submitType != "" {
+			cmdFn(ChangeRequest{ChangeRequested: w.submitType, Data: string(w.text)})
+		}
+	} else if inpututil.IsKeyJustReleased(ebiten.KeyEscape) {
+		w.blur()
+	}
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *TextInput) NotifyCallback(v input.StrokeEvent) {
+	if w.disabled {
+		return
+	}
+	switch v.Event {
+	case input.Tap:
+		if util.InRect(v.X, v.Y, w.OffsetRect) {
+			w.focus()
+		} else if focusedInput == w {
+			w.blur()
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"image/color"
+	"strconv"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/util"
+)
+
+// TimelineMarker marks an interesting position in the undo history
+type TimelineMarker struct {
+	Position int    // index into the undo stack
+	Kind     string // "bookmark", "foundation" or "recycle"
+}
+
+var timelineMarkerColors = map[string]color.RGBA{
+	"bookmark":   {R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	"foundation": {R: 0xff, G: 0xd7, B: 0x00, A: 0xff}, // Gold
+	"recycle":    {R: 0x64, G: 0x95, B: 0xed, A: 0xff}, // CornflowerBlue
+}
+
+// Timeline shows the undo history as a line from the start of the deal to the current position.
+// Dragging along it asks for a preview of the position under the finger, letting go jumps there
+type Timeline struct {
+	WidgetBase
+	length     int // number of positions
+	markers    []TimelineMarker
+	thumb      int // position being shown
+	previewing bool
+}
+
+func (w *Timeline) createImg() *ebiten.Image {
+	if w.width == 0 || w.height == 0 {
+		return nil
+	}
+	dc := gg.NewContext(w.width, w.height)
+	mid := float64(w.height) / 2
+
+	dc.SetRGBA(1, 1, 1, 0.5)
+	dc.SetLineWidth(2)
+	dc.DrawLine(0, mid, float64(w.width), mid)
+	dc.Stroke()
+
+	// draw the less important markers first, so bookmarks stay on top
+	for _, kind := range []string{"foundation", "recycle", "bookmark"} {
+		dc.SetColor(timelineMarkerColors[kind])
+		for _, m := range w.markers {
+			if m.Kind != kind {
+				continue
+			}
+			x := w.positionToX(m.Position)
+			if kind == "bookmark" {
+				dc.DrawRectangle(x-1, 4, 3, float64(w.height)-8)
+			} else {
+				dc.DrawRectangle(x-1, mid-8, 2, 16)
+			}
+			dc.Fill()
+		}
+	}
+
+	dc.SetRGBA(1, 1, 1, 1)
+	dc.DrawCircle(w.positionToX(w.thumb), mid, 8)
+	dc.Fill()
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+func (w *Timeline) positionToX(position int) float64 {
+	if w.length < 2 {
+		return 8
+	}
+	return 8 + float64(position)*float64(w.width-16)/float64(w.length-1)
+}
+
+func (w *Timeline) xToPosition(x int) int {
+	x0, _, _, _ := w.OffsetRect()
+	if w.length < 2 || w.width <= 16 {
+		return 0
+	}
+	position := ((x-x0-8)*(w.length-1) + (w.width-16)/2) / (w.width - 16)
+	return util.ClampInt(position, 0, w.length-1)
+}
+
+// NewTimeline creates a new Timeline
+func NewTimeline(parent Container) *Timeline {
+	// widget x, y will be set by LayoutWidgets
+	w := &Timeline{WidgetBase: WidgetBase{parent: parent, img: nil, width: 0, height: 40, align: -1}}
+	return w
+}
+
+// Activate tells the input we need notifications
+func (w *Timeline) Activate() {
+	w.disabled = false
+	w.img = w.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (w *Timeline) Deactivate() {
+	w.disabled = true
+	w.img = w.createImg()
+}
+
+func (w *Timeline) moveThumb(position int, requestType string) {
+	if position != w.thumb {
+		w.thumb = position
+		w.img = w.createImg()
+	}
+	cmdFn(ChangeRequest{ChangeRequested: requestType, Data: strconv.Itoa(position)})
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (w *Timeline) NotifyCallback(v input.StrokeEvent) {
+	if w.disabled || w.length < 2 {
+		return
+	}
+	switch v.Event {
+	case input.Move:
+		// only follow strokes that started on the timeline
+		dx, dy := v.Stroke.PositionDiff()
+		if !w.previewing && !util.InRect(v.X-dx, v.Y-dy, w.OffsetRect) {
+			return
+		}
+		w.previewing = true
+		if position := w.xToPosition(v.X); position != w.thumb {
+			w.moveThumb(position, "Timeline preview")
+		}
+	case input.Stop:
+		if w.previewing {
+			w.previewing = false
+			w.moveThumb(w.xToPosition(v.X), "Timeline goto")
+		}
+	case input.Tap:
+		w.previewing = false
+		if util.InRect(v.X, v.Y, w.OffsetRect) {
+			w.moveThumb(w.xToPosition(v.X), "Timeline goto")
+		}
+	case input.Cancel:
+		if w.previewing {
+			w.previewing = false
+			w.moveThumb(w.length-1, "Timeline preview")
+		}
+	}
+}
+
+// TimelineBar holds the Timeline, above the statusbar; it's only there when the user asks for it
+type TimelineBar struct {
+	BarBase
+	visible bool
+}
+
+// NewTimelineBar creates a new, hidden, TimelineBar
+func NewTimelineBar() *TimelineBar {
+	// img will created first time it's drawn if width == 0
+	tb := &TimelineBar{BarBase: BarBase{x: 0, y: 0, width: 0, height: 48}}
+	tb.widgets = []Widget{NewTimeline(tb)}
+	return tb
+}
+
+// Layout implements Ebiten's Layout
+func (tb *TimelineBar) Layout(outsideWidth, outsideHeight int) (int, int) {
+	// override BarBase.Layout to sit on top of the statusbar, and stretch the timeline to fit
+	if tb.img == nil || outsideWidth != tb.width {
+		tb.width = outsideWidth
+		tb.img = tb.createImg()
+		w := tb.widgets[0].(*Timeline)
+		w.width = util.Max(outsideWidth-48, 0)
+		w.img = w.createImg()
+		tb.LayoutWidgets()
+	}
+	tb.x, tb.y = 0, outsideHeight-24-tb.height
+	return outsideWidth, outsideHeight
+}
+
+// Rect gives the screen position and extent of this container, which is nothing when it's hidden
+func (tb *TimelineBar) Rect() (x0, y0, x1, y1 int) {
+	if !tb.visible {
+		return 0, 0, 0, 0
+	}
+	return tb.BarBase.Rect()
+}
+
+// Show the timeline
+func (tb *TimelineBar) Show() {
+	tb.visible = true
+}
+
+// Hide the timeline
+func (tb *TimelineBar) Hide() {
+	tb.visible = false
+}
+
+// Visible is the timeline
+func (tb *TimelineBar) Visible() bool {
+	return tb.visible
+}
+
+// Draw the timeline, if it's visible
+func (tb *TimelineBar) Draw(screen *ebiten.Image) {
+	if tb.visible {
+		tb.BarBase.Draw(screen)
+	}
+}
+
+// ToggleTimeline shows or hides the timeline, and returns true if it is now showing
+func (u *UI) ToggleTimeline() bool {
+	if u.timelineBar.Visible() {
+		u.timelineBar.Hide()
+		u.fabbar.lift = 0
+	} else {
+		u.timelineBar.Show()
+		u.fabbar.lift = u.timelineBar.height
+	}
+	return u.timelineBar.Visible()
+}
+
+// TimelineVisible returns true if the timeline is showing
+func (u *UI) TimelineVisible() bool {
+	return u.timelineBar.Visible()
+}
+
+// SetTimeline tells the timeline how long the undo history is, and where the interesting positions are
+func (u *UI) SetTimeline(length int, markers []TimelineMarker) {
+	w := u.timelineBar.widgets[0].(*Timeline)
+	if w.previewing {
+		return
+	}
+	w.length = length
+	w.markers = markers
+	w.thumb = util.Max(length-1, 0)
+	w.img = w.createImg()
+}
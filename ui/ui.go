@@ -16,17 +16,23 @@ var (
 
 // UI encapsulates a complete user interface that can be rendered onto the screen.
 type UI struct {
-	toolbar        *Toolbar
-	statusbar      *Statusbar
-	fabbar         *FABBar
-	navDrawer      *NavDrawer
-	settingsDrawer *SettingsDrawer
-	variantPicker  *Picker
-	textDrawer     *TextDrawer
-	containers     []Container
-	bars           []Container
-	drawers        []Container
-	toastManager   *ToastManager
+	toolbar          *Toolbar
+	statusbar        *Statusbar
+	fabbar           *FABBar
+	timelineBar      *TimelineBar
+	navDrawer        *NavDrawer
+	settingsDrawer   *SettingsDrawer
+	variantPicker    *Picker
+	textDrawer       *TextDrawer
+	customDrawer     *CustomDrawer
+	slotsDrawer      *SlotsDrawer
+	statisticsDrawer *StatisticsDrawer
+	dailyDrawer      *DailyDrawer
+	listDrawers      map[string]*ListDrawer // see ShowListDrawer
+	containers       []Container
+	bars             []Container
+	drawers          []Container
+	toastManager     *ToastManager
 }
 
 var cmdFn func(interface{})
@@ -42,14 +48,20 @@ func New(fn func(interface{})) *UI {
 	ui.toolbar = NewToolbar()
 	ui.statusbar = NewStatusbar()
 	ui.fabbar = NewFABBar()
+	ui.timelineBar = NewTimelineBar()
 	ui.navDrawer = NewNavDrawer()
 	ui.settingsDrawer = NewSettingsDrawer()
 	ui.variantPicker = NewVariantPicker()
 	ui.textDrawer = NewTextDrawer() // contents are added when shown
-
-	ui.bars = []Container{ui.toolbar, ui.statusbar, ui.fabbar}
-	ui.drawers = []Container{ui.navDrawer, ui.settingsDrawer, ui.variantPicker, ui.textDrawer}
-	ui.containers = []Container{ui.toolbar, ui.statusbar, ui.fabbar, ui.navDrawer, ui.settingsDrawer, ui.variantPicker, ui.textDrawer}
+	ui.customDrawer = NewCustomDrawer()
+	ui.slotsDrawer = NewSlotsDrawer()
+	ui.statisticsDrawer = NewStatisticsDrawer()
+	ui.dailyDrawer = NewDailyDrawer()
+	ui.listDrawers = make(map[string]*ListDrawer)
+
+	ui.bars = []Container{ui.toolbar, ui.statusbar, ui.timelineBar, ui.fabbar}
+	ui.drawers = []Container{ui.navDrawer, ui.settingsDrawer, ui.variantPicker, ui.textDrawer, ui.customDrawer, ui.slotsDrawer, ui.statisticsDrawer, ui.dailyDrawer}
+	ui.containers = append(append([]Container{}, ui.bars...), ui.drawers...)
 
 	return ui
 }
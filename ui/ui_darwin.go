@@ -3,6 +3,7 @@ package ui
 import (
 	"log"
 	"os/exec"
+	"strings"
 )
 
 func OpenBrowserWindow(url string) {
@@ -14,3 +15,10 @@ func OpenBrowserWindow(url string) {
 		}
 	}
 }
+
+// CopyToClipboard puts some text on the clipboard, so it can be pasted somewhere else
+func CopyToClipboard(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
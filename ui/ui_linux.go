@@ -3,6 +3,7 @@ package ui
 import (
 	"log"
 	"os/exec"
+	"strings"
 )
 
 func OpenBrowserWindow(url string) {
@@ -14,3 +15,14 @@ func OpenBrowserWindow(url string) {
 		}
 	}
 }
+
+// CopyToClipboard puts some text on the clipboard, so it can be pasted somewhere else.
+// It needs xclip, or failing that xsel
+func CopyToClipboard(text string) error {
+	cmd := exec.Command("xclip", "-selection", "clipboard")
+	if _, err := exec.LookPath("xclip"); err != nil {
+		cmd = exec.Command("xsel", "--clipboard", "--input")
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
@@ -1,9 +1,21 @@
 package ui
 
 import (
+	"errors"
 	"syscall/js"
 )
 
 func OpenBrowserWindow(url string) {
 	js.Global().Get("window").Call("open", url)
 }
+
+// CopyToClipboard puts some text on the clipboard, so it can be pasted somewhere else.
+// The browser does it in the background, and may refuse
+func CopyToClipboard(text string) error {
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if clipboard.IsUndefined() {
+		return errors.New("the browser has no clipboard")
+	}
+	clipboard.Call("writeText", text)
+	return nil
+}
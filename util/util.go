@@ -237,6 +237,15 @@ func Contains(a []string, x string) bool {
 	return false
 }
 
+// FormatDuration returns a duration as a clock would show it, eg 4:05 or 1:02:03
+func FormatDuration(d time.Duration) string {
+	secs := int(d / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
 // Duration of a func call
 // Arguments to a defer statement are immediately evaluated and stored.
 // The deferred function receives the pre-evaluated values when its invoked.